@@ -53,5 +53,8 @@ func main() {
 		log.Fatalf("❌ Graceful shutdown failed: %v", err)
 	}
 
+	// Flush background workers (analytics buffer, etc.)
+	routes.Shutdown()
+
 	log.Println("✅ Server shutdown complete")
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 )
 
 type Config struct {
@@ -10,6 +11,38 @@ type Config struct {
 	CloudinaryCloudName string
 	CloudinaryAPIKey    string
 	CloudinaryAPISecret string
+
+	// Referral rewards are granted once a referred applicant reaches this stage
+	ReferralRewardStage   string
+	ReferralRewardCredits int
+
+	// Default resume parser: auto (Gemini, falling back to heuristics), llm or heuristic
+	ResumeParserMode string
+
+	// Background resume parsing
+	ParseWorkers           int
+	ParseMaxAttempts       int
+	ParseJobRetentionHours int
+	GeminiTimeoutSeconds   int
+	ParseCacheTTLHours     int // 0 disables caching of Gemini results
+
+	// Limits on reading an uploaded document
+	ResumeMaxPages        int
+	ExtractTimeoutSeconds int
+	ExtractMaxConcurrent  int
+
+	// Bulk resume imports
+	BulkImportConcurrency int
+	BulkImportMaxFiles    int
+
+	// Directory original resume documents are stored in
+	ResumeStorageDir string
+
+	// Whether resume PII is withheld from Gemini for tenants without their own setting
+	PIIRedaction bool
+
+	// Reverse proxies whose X-Forwarded-For is believed: addresses and CIDR ranges, comma-separated
+	TrustedProxies string
 }
 
 var AppConfig *Config
@@ -33,5 +66,65 @@ func LoadConfig() {
 		CloudinaryCloudName: cloudName,
 		CloudinaryAPIKey:    cloudKey,
 		CloudinaryAPISecret: cloudSecret,
+
+		ReferralRewardStage:   getEnv("REFERRAL_REWARD_STAGE", "hired"),
+		ReferralRewardCredits: getEnvInt("REFERRAL_REWARD_CREDITS", 5),
+
+		ResumeParserMode: getEnv("RESUME_PARSER_MODE", "auto"),
+
+		ParseWorkers:           getEnvInt("PARSE_WORKERS", 4),
+		ParseMaxAttempts:       getEnvInt("PARSE_MAX_ATTEMPTS", 3),
+		ParseJobRetentionHours: getEnvInt("PARSE_JOB_RETENTION_HOURS", 24),
+		GeminiTimeoutSeconds:   getEnvInt("GEMINI_TIMEOUT_SECONDS", 60),
+		ParseCacheTTLHours:     getEnvInt("PARSE_CACHE_TTL_HOURS", 168),
+
+		ResumeMaxPages:        getEnvInt("RESUME_MAX_PAGES", 50),
+		ExtractTimeoutSeconds: getEnvInt("EXTRACT_TIMEOUT_SECONDS", 30),
+		ExtractMaxConcurrent:  getEnvInt("EXTRACT_MAX_CONCURRENT", 8),
+
+		BulkImportConcurrency: getEnvInt("BULK_IMPORT_CONCURRENCY", 4),
+		BulkImportMaxFiles:    getEnvInt("BULK_IMPORT_MAX_FILES", 500),
+
+		ResumeStorageDir: getEnv("RESUME_STORAGE_DIR", "uploads/resumes"),
+
+		PIIRedaction: getEnvBool("PII_REDACTION", false),
+
+		TrustedProxies: getEnv("TRUSTED_PROXIES", ""),
+	}
+}
+
+// getEnv returns an optional setting or its default
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// getEnvBool returns an optional boolean setting or its default
+func getEnvBool(key string, fallback bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Printf("⚠️ Invalid %s=%q, using %t", key, v, fallback)
+		return fallback
+	}
+	return b
+}
+
+// getEnvInt returns an optional integer setting or its default
+func getEnvInt(key string, fallback int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("⚠️ Invalid %s=%q, using %d", key, v, fallback)
+		return fallback
 	}
+	return n
 }
@@ -47,6 +47,66 @@ func InitDB() *gorm.DB {
 
 	DB.AutoMigrate(&model.Job{})
 
+	// Concurrent applies could once store the same application twice. Keep
+	// the first of each so the unique (job_id, user_id) index can be built
+	if DB.Migrator().HasTable(&model.JobApplication{}) {
+		if err := DB.Exec("DELETE FROM job_applications WHERE id NOT IN (SELECT MIN(id) FROM job_applications GROUP BY job_id, user_id)").Error; err != nil {
+			log.Fatalf("❌ Removing duplicate job applications failed: %v", err)
+		}
+		if DB.Migrator().HasTable(&model.ApplicationAnswer{}) {
+			DB.Exec("DELETE FROM application_answers WHERE application_id NOT IN (SELECT id FROM job_applications)")
+		}
+	}
+	if err := DB.AutoMigrate(&model.JobApplication{}, &model.JobEvent{}); err != nil {
+		log.Fatalf("❌ Job analytics table migration failed: %v", err)
+	}
+	log.Println("✅ Job application and event tables migrated successfully")
+
+	if err := DB.AutoMigrate(&model.ExchangeRate{}); err != nil {
+		log.Fatalf("❌ Exchange rate table migration failed: %v", err)
+	}
+	log.Println("✅ Exchange rate table migrated successfully")
+
+	if err := DB.AutoMigrate(&model.ScreeningQuestion{}, &model.ApplicationAnswer{}); err != nil {
+		log.Fatalf("❌ Screening tables migration failed: %v", err)
+	}
+	log.Println("✅ Screening question and answer tables migrated successfully")
+
+	if err := DB.AutoMigrate(&model.Referral{}, &model.ReferralClick{}, &model.ReferralReward{}); err != nil {
+		log.Fatalf("❌ Referral tables migration failed: %v", err)
+	}
+	log.Println("✅ Referral tables migrated successfully")
+
+	if err := DB.AutoMigrate(&model.ParseJob{}, &model.ParseCacheEntry{}); err != nil {
+		log.Fatalf("❌ Parse job table migration failed: %v", err)
+	}
+	log.Println("✅ Parse job and parse cache tables migrated successfully")
+
+	if err := DB.AutoMigrate(&model.BulkImport{}, &model.BulkImportItem{}, &model.TalentPoolEntry{}); err != nil {
+		log.Fatalf("❌ Bulk import tables migration failed: %v", err)
+	}
+	log.Println("✅ Bulk import and talent pool tables migrated successfully")
+
+	if err := DB.AutoMigrate(&model.Resume{}); err != nil {
+		log.Fatalf("❌ Resume table migration failed: %v", err)
+	}
+	log.Println("✅ Resume table migrated successfully")
+
+	if err := DB.AutoMigrate(&model.TenantSettings{}); err != nil {
+		log.Fatalf("❌ Tenant settings table migration failed: %v", err)
+	}
+	log.Println("✅ Tenant settings table migrated successfully")
+
+	if err := DB.AutoMigrate(&model.Skill{}, &model.UnknownSkill{}); err != nil {
+		log.Fatalf("❌ Skill taxonomy tables migration failed: %v", err)
+	}
+	log.Println("✅ Skill taxonomy tables migrated successfully")
+
+	if err := DB.AutoMigrate(&model.ResumeRender{}); err != nil {
+		log.Fatalf("❌ Resume render table migration failed: %v", err)
+	}
+	log.Println("✅ Resume render table migrated successfully")
+
 	// Debug: List all tables
 	var tables []string
 	DB.Raw("SELECT name FROM sqlite_master WHERE type='table'").Scan(&tables)
@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/service"
+	"github.com/satyam-svg/resume-parser/internal/utils"
+)
+
+// trackableEvents are the event types clients may report directly.
+// Applications are recorded when the application itself is created.
+var trackableEvents = map[string]bool{
+	model.EventView:       true,
+	model.EventDetailOpen: true,
+	model.EventApplyClick: true,
+}
+
+// TrackJobEvent records a client-side engagement event: POST /jobs/{jobID}/events.
+// The viewer is the user of the bearer token, if any.
+func (jc *JobController) TrackJobEvent(w http.ResponseWriter, r *http.Request, id string) {
+	jobID, err := strconv.Atoi(id)
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		Type string `json:"type"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid input", http.StatusBadRequest)
+		return
+	}
+	if !trackableEvents[body.Type] {
+		http.Error(w, "Invalid event type. Must be view, detail_open or apply_click", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := jc.Service.GetJobByID(uint(jobID)); err != nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	jc.recordEvent(r, uint(jobID), body.Type)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// GetRecruiterAnalytics returns engagement analytics for a recruiter's jobs:
+// GET /jobs/recruiter/{recruiterID}/analytics?from=YYYY-MM-DD&to=YYYY-MM-DD
+// with "Authorization: Bearer <token>" of the recruiter.
+func (jc *JobController) GetRecruiterAnalytics(w http.ResponseWriter, r *http.Request, recruiterID string) {
+	if recruiterID == "" {
+		http.Error(w, "Recruiter ID is required", http.StatusBadRequest)
+		return
+	}
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	if userID.String() != recruiterID {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -30)
+	if v := r.URL.Query().Get("from"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			http.Error(w, "Invalid from date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		from = t
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			http.Error(w, "Invalid to date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		to = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	if from.After(to) {
+		http.Error(w, "from must be before to", http.StatusBadRequest)
+		return
+	}
+
+	analytics, err := jc.Analytics.RecruiterAnalytics(recruiterID, from, to)
+	if err != nil {
+		http.Error(w, "Failed to fetch analytics", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(analytics)
+}
+
+// recordEvent queues an engagement event for the job. Signed-in viewers are
+// told apart by their bearer token's user, others by address and user
+// agent. It is a no-op when analytics are not configured.
+func (jc *JobController) recordEvent(r *http.Request, jobID uint, eventType string) {
+	if jc.Analytics == nil {
+		return
+	}
+
+	ua := r.UserAgent()
+	event := model.JobEvent{
+		JobID:     jobID,
+		Type:      eventType,
+		VisitorID: service.VisitorID(utils.ClientIP(r), ua),
+	}
+	if uid, err := utils.BearerUserID(r); err == nil {
+		event.UserID = &uid
+		event.VisitorID = service.VisitorID("user:"+uid.String(), "")
+	}
+	jc.Analytics.Record(event, ua)
+}
@@ -0,0 +1,298 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/service"
+)
+
+type ApplicationController struct {
+	Service *service.ApplicationService
+	Jobs    *service.JobService
+}
+
+type ApplicantEntry struct {
+	ApplicationID uint                      `json:"application_id"`
+	AppliedAt     time.Time                 `json:"applied_at"`
+	Stage         string                    `json:"stage"`
+	Referred      bool                      `json:"referred"`
+	ResumeID      *uuid.UUID                `json:"resume_id,omitempty"` // download via /user/{userID}/resumes/{resumeID}/download
+	KnockedOut    bool                      `json:"knocked_out"`
+	Answers       []model.ApplicationAnswer `json:"answers"`
+	Applicant     interface{}               `json:"applicant"`
+}
+
+// GetQuestions lists a job's screening questions: GET /jobs/{jobID}/questions
+func (ac *ApplicationController) GetQuestions(w http.ResponseWriter, r *http.Request, id string) {
+	jobID, ok := ac.parseJobID(w, id)
+	if !ok {
+		return
+	}
+
+	questions, err := ac.Service.GetQuestions(jobID)
+	if err != nil {
+		http.Error(w, "Failed to fetch questions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(questions)
+}
+
+// SetQuestions replaces a job's screening questions: POST /jobs/{jobID}/questions
+// with "Authorization: Bearer <token>" of the job's recruiter. It replies 409
+// once the job has applications.
+func (ac *ApplicationController) SetQuestions(w http.ResponseWriter, r *http.Request, id string) {
+	jobID, ok := ac.requireJobRecruiter(w, r, id)
+	if !ok {
+		return
+	}
+
+	var questions []model.ScreeningQuestion
+	if err := json.NewDecoder(r.Body).Decode(&questions); err != nil {
+		http.Error(w, "Invalid input", http.StatusBadRequest)
+		return
+	}
+
+	saved, err := ac.Service.ReplaceQuestions(jobID, questions)
+	switch {
+	case errors.Is(err, service.ErrQuestionsLocked):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+}
+
+// Apply submits an application with screening answers: POST /jobs/{jobID}/apply
+// with the applicant's "Authorization: Bearer <token>".
+func (ac *ApplicationController) Apply(w http.ResponseWriter, r *http.Request, id string) {
+	applicantID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	jobID, ok := ac.parseJobID(w, id)
+	if !ok {
+		return
+	}
+
+	var input service.ApplyInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid input", http.StatusBadRequest)
+		return
+	}
+
+	user, err := ac.Jobs.GetUserByID(applicantID.String())
+	if err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	app, err := ac.Service.Apply(jobID, user.ID, input)
+	switch {
+	case errors.Is(err, service.ErrAlreadyApplied), errors.Is(err, service.ErrJobNotPublished):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case errors.Is(err, service.ErrInvalidAnswer),
+		errors.Is(err, service.ErrReferralNotFound),
+		errors.Is(err, service.ErrReferralMismatch),
+		errors.Is(err, service.ErrSelfReferral),
+		errors.Is(err, service.ErrRecruiterReferral),
+		errors.Is(err, service.ErrResumeNotFound):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, "Failed to submit application", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":        "Application submitted",
+		"application_id": app.ID,
+		"stage":          app.Stage,
+		"knocked_out":    app.KnockedOut,
+		"referred":       app.ReferralID != nil,
+		"resume_id":      app.ResumeID,
+	})
+}
+
+// UpdateStage moves an application through the hiring pipeline:
+// POST /applications/{applicationID}/stage {"stage": "interview"} with
+// "Authorization: Bearer <token>" of the job's recruiter.
+func (ac *ApplicationController) UpdateStage(w http.ResponseWriter, r *http.Request, id string) {
+	recruiterID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	applicationID, err := strconv.Atoi(id)
+	if err != nil {
+		http.Error(w, "Invalid application ID", http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		Stage string `json:"stage"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid input", http.StatusBadRequest)
+		return
+	}
+
+	app, err := ac.Service.UpdateStage(uint(applicationID), recruiterID, body.Stage)
+	switch {
+	case errors.Is(err, service.ErrNotJobRecruiter):
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	case errors.Is(err, service.ErrInvalidStage):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, service.ErrApplicationNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, "Failed to update stage", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"application_id": app.ID,
+		"stage":          app.Stage,
+	})
+}
+
+// GetApplicants lists a job's applicants, filtered by screening answers:
+// GET /jobs/{jobID}/applicants?answer_{questionID}=yes&answer_{questionID}_min=2&knocked_out=false
+// with "Authorization: Bearer <token>" of the job's recruiter.
+func (ac *ApplicationController) GetApplicants(w http.ResponseWriter, r *http.Request, id string) {
+	jobID, ok := ac.requireJobRecruiter(w, r, id)
+	if !ok {
+		return
+	}
+
+	filters, err := parseAnswerFilters(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	apps, err := ac.Service.GetApplicants(jobID, filters, r.URL.Query().Get("knocked_out") == "false")
+	if err != nil {
+		http.Error(w, "Failed to fetch applicants", http.StatusInternalServerError)
+		return
+	}
+
+	entries := make([]ApplicantEntry, 0, len(apps))
+	for _, app := range apps {
+		entries = append(entries, ApplicantEntry{
+			ApplicationID: app.ID,
+			AppliedAt:     app.CreatedAt,
+			Stage:         app.Stage,
+			Referred:      app.ReferralID != nil,
+			ResumeID:      app.ResumeID,
+			KnockedOut:    app.KnockedOut,
+			Answers:       app.Answers,
+			Applicant:     filterUserResponse(app.User),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"job_id":     jobID,
+		"applicants": entries,
+	})
+}
+
+func (ac *ApplicationController) parseJobID(w http.ResponseWriter, id string) (uint, bool) {
+	jobID, err := strconv.Atoi(id)
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return 0, false
+	}
+	if _, err := ac.Jobs.GetJobByID(uint(jobID)); err != nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return 0, false
+	}
+	return uint(jobID), true
+}
+
+// requireJobRecruiter parses the job ID and checks that the bearer token is
+// the job's recruiter, replying 400, 401, 403 or 404 otherwise.
+func (ac *ApplicationController) requireJobRecruiter(w http.ResponseWriter, r *http.Request, id string) (uint, bool) {
+	recruiterID, ok := requireUser(w, r)
+	if !ok {
+		return 0, false
+	}
+	jobID, err := strconv.Atoi(id)
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return 0, false
+	}
+	job, err := ac.Jobs.GetJobByID(uint(jobID))
+	if err != nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return 0, false
+	}
+	if job.RecruiterID != recruiterID {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return 0, false
+	}
+	return uint(jobID), true
+}
+
+// parseAnswerFilters reads answer_{id}, answer_{id}_min and answer_{id}_max query parameters.
+func parseAnswerFilters(r *http.Request) ([]service.AnswerFilter, error) {
+	byQuestion := make(map[uint]*service.AnswerFilter)
+	for key, values := range r.URL.Query() {
+		if !strings.HasPrefix(key, "answer_") || len(values) == 0 {
+			continue
+		}
+
+		parts := strings.SplitN(strings.TrimPrefix(key, "answer_"), "_", 2)
+		qid, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, errors.New("Invalid answer filter: " + key)
+		}
+		f, ok := byQuestion[uint(qid)]
+		if !ok {
+			f = &service.AnswerFilter{QuestionID: uint(qid)}
+			byQuestion[uint(qid)] = f
+		}
+
+		if len(parts) == 1 {
+			f.Equals = values[0]
+			continue
+		}
+		n, err := strconv.ParseFloat(values[0], 64)
+		if err != nil {
+			return nil, errors.New("Invalid number for " + key)
+		}
+		switch parts[1] {
+		case "min":
+			f.Min = &n
+		case "max":
+			f.Max = &n
+		default:
+			return nil, errors.New("Invalid answer filter: " + key)
+		}
+	}
+
+	filters := make([]service.AnswerFilter, 0, len(byQuestion))
+	for _, f := range byQuestion {
+		filters = append(filters, *f)
+	}
+	return filters, nil
+}
@@ -1,11 +1,21 @@
 package controller
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"log"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/satyam-svg/resume-parser/config"
 	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/service"
 	"github.com/satyam-svg/resume-parser/internal/utils"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -29,9 +39,10 @@ type SignupRequest struct {
 	Portfolio  string             `json:"portfolio"`
 	Skills     string             `json:"skills"`
 	Image      string             `json:"image"`
-	Role       string             `json:"role"` // recruiter, applicant, admin
+	Role       string             `json:"role"` // recruiter or applicant
 	Education  []model.Education  `json:"education"`
 	Experience []model.Experience `json:"experience"`
+	ClaimToken string             `json:"claim_token"` // claims a draft profile imported with this email
 }
 
 // ---------- Filtered Response ----------
@@ -60,6 +71,7 @@ func filterUserResponse(user model.User) interface{} {
 		FullName:          user.FullName,
 		Title:             user.Title,
 		Location:          user.Location,
+		Geo:               user.Geo,
 		Email:             user.Email,
 		Phone:             user.Phone,
 		CurrentCompany:    user.CurrentCompany,
@@ -69,8 +81,10 @@ func filterUserResponse(user model.User) interface{} {
 		Skills:            user.Skills,
 		Image:             user.Image,
 		Role:              user.Role,
+		Language:          user.Language,
 		Education:         user.Education,
 		Experience:        user.Experience,
+		ExperienceSummary: utils.SummarizeExperience(user.Experience, time.Now()),
 		ApplicationsCount: int(count),
 	}
 }
@@ -83,23 +97,42 @@ func Signup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	input.Email = strings.ToLower(strings.TrimSpace(input.Email))
 	if input.Email == "" || input.Password == "" {
 		http.Error(w, "Email and password are required", http.StatusBadRequest)
 		return
 	}
 
 	// Validate role
-	allowedRoles := map[string]bool{"recruiter": true, "applicant": true, "admin": true}
+	allowedRoles := map[string]bool{"recruiter": true, "applicant": true}
 	if _, ok := allowedRoles[input.Role]; !ok {
-		http.Error(w, "Invalid role. Must be recruiter, applicant, or admin", http.StatusBadRequest)
+		http.Error(w, "Invalid role. Must be recruiter or applicant", http.StatusBadRequest)
 		return
 	}
 
-	// Check if user already exists
+	// Check if user already exists. A draft profile created from an imported
+	// resume is claimed by the candidate signing up with its email and the
+	// claim token mailed to that address.
 	var existingUser model.User
-	if err := config.DB.Where("email = ?", input.Email).First(&existingUser).Error; err == nil {
-		http.Error(w, "User already exists", http.StatusConflict)
-		return
+	if err := config.DB.Where("LOWER(email) = ?", input.Email).First(&existingUser).Error; err == nil {
+		if existingUser.Status != model.UserStatusDraft {
+			http.Error(w, "User already exists", http.StatusConflict)
+			return
+		}
+		if !validClaimToken(existingUser, input.ClaimToken) {
+			if err := sendClaimToken(&existingUser); err != nil {
+				log.Printf("❌ Sending claim token for draft %s failed: %v", existingUser.ID, err)
+				http.Error(w, "Failed to send the claim email", http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"message": "A profile was imported for this email. Sign up again with the claim_token sent to it to claim the profile.",
+				"email":   existingUser.Email,
+			})
+			return
+		}
 	}
 
 	// Hash password
@@ -116,18 +149,36 @@ func Signup(w http.ResponseWriter, r *http.Request) {
 		FullName:       input.FullName,
 		Title:          input.Title,
 		Location:       input.Location,
+		Geo:            utils.ResolveLocation(input.Location),
 		Phone:          input.Phone,
 		CurrentCompany: input.Company,
 		LinkedIn:       input.LinkedIn,
 		GitHub:         input.GitHub,
 		Portfolio:      input.Portfolio,
-		Skills:         input.Skills,
+		Skills:         service.NormalizeSkills(input.Skills),
 		Image:          input.Image,
 		Role:           input.Role,
+		Status:         model.UserStatusActive,
 	}
 
 	tx := config.DB.Begin()
-	if err := tx.Create(&user).Error; err != nil {
+	if existingUser.Status == model.UserStatusDraft {
+		// Keep the imported details the candidate did not fill in
+		user.ID = existingUser.ID
+		if input.Location == "" {
+			user.Geo = existingUser.Geo
+		}
+		if err := tx.Model(&existingUser).Updates(user).Error; err != nil {
+			tx.Rollback()
+			http.Error(w, "User creation failed", http.StatusInternalServerError)
+			return
+		}
+		if err := tx.Model(&existingUser).Updates(map[string]interface{}{"claim_token_hash": "", "claim_expires_at": nil}).Error; err != nil {
+			tx.Rollback()
+			http.Error(w, "User creation failed", http.StatusInternalServerError)
+			return
+		}
+	} else if err := tx.Create(&user).Error; err != nil {
 		tx.Rollback()
 		http.Error(w, "User creation failed", http.StatusInternalServerError)
 		return
@@ -136,6 +187,7 @@ func Signup(w http.ResponseWriter, r *http.Request) {
 	// Save education
 	for _, edu := range input.Education {
 		edu.UserID = user.ID
+		edu.Period = utils.ParseDateRange(edu.Years)
 		if err := tx.Create(&edu).Error; err != nil {
 			tx.Rollback()
 			http.Error(w, "Failed to add education", http.StatusInternalServerError)
@@ -146,6 +198,7 @@ func Signup(w http.ResponseWriter, r *http.Request) {
 	// Save experience
 	for _, exp := range input.Experience {
 		exp.UserID = user.ID
+		exp.Period = utils.ParseDateRange(exp.Years)
 		if err := tx.Create(&exp).Error; err != nil {
 			tx.Rollback()
 			http.Error(w, "Failed to add experience", http.StatusInternalServerError)
@@ -174,6 +227,57 @@ func Signup(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// ---------- Draft Claims ----------
+const (
+	claimTokenTTL       = 24 * time.Hour
+	claimResendInterval = 10 * time.Minute
+)
+
+// sendClaimToken mails a new claim token to a draft profile's address. A
+// token sent in the last claimResendInterval is not replaced, so repeated
+// signups cannot flood the address.
+func sendClaimToken(draft *model.User) error {
+	if draft.ClaimExpiresAt != nil && time.Until(*draft.ClaimExpiresAt) > claimTokenTTL-claimResendInterval {
+		return nil
+	}
+
+	previous := map[string]interface{}{"claim_token_hash": draft.ClaimTokenHash, "claim_expires_at": draft.ClaimExpiresAt}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(raw)
+	expires := time.Now().Add(claimTokenTTL)
+	if err := config.DB.Model(draft).Updates(map[string]interface{}{
+		"claim_token_hash": hashClaimToken(token),
+		"claim_expires_at": expires,
+	}).Error; err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("A recruiter imported your resume. To claim the profile, sign up with this email and the claim token below within 24 hours:\n\n%s\n\nIf you did not try to sign up, you can ignore this email.", token)
+	if err := utils.SendMail(draft.Email, "Claim your profile", body); err != nil {
+		// Keep any token sent before, and let the next signup try again
+		config.DB.Model(draft).Updates(previous)
+		return err
+	}
+	return nil
+}
+
+// validClaimToken reports whether token is the unexpired claim token of a draft profile.
+func validClaimToken(draft model.User, token string) bool {
+	if token == "" || draft.ClaimTokenHash == "" || draft.ClaimExpiresAt == nil || time.Now().After(*draft.ClaimExpiresAt) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hashClaimToken(token)), []byte(draft.ClaimTokenHash)) == 1
+}
+
+func hashClaimToken(token string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(token)))
+	return hex.EncodeToString(sum[:])
+}
+
 // ---------- Login ----------
 func Login(w http.ResponseWriter, r *http.Request) {
 	var input LoginRequest
@@ -188,7 +292,7 @@ func Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var user model.User
-	if err := config.DB.Where("email = ?", input.Email).First(&user).Error; err != nil {
+	if err := config.DB.Where("LOWER(email) = ?", strings.ToLower(strings.TrimSpace(input.Email))).First(&user).Error; err != nil {
 		http.Error(w, "Invalid email or password", http.StatusUnauthorized)
 		return
 	}
@@ -296,3 +400,49 @@ func GetUserByID(w http.ResponseWriter, r *http.Request) {
 		"user": filterUserResponse(user),
 	})
 }
+
+// ---------- Admin Check ----------
+// requireAdmin lets the request through when its "Authorization: Bearer"
+// token belongs to an admin, and replies 401 or 403 otherwise.
+func requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return false
+	}
+	if !isAdmin(userID) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func isAdmin(userID uuid.UUID) bool {
+	var user model.User
+	return config.DB.Select("role").First(&user, "id = ?", userID).Error == nil && user.Role == "admin"
+}
+
+// requireUser returns the user of the request's "Authorization: Bearer"
+// token, and replies 401 when there is no valid one.
+func requireUser(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userID, err := utils.BearerUserID(r)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// requireOwner lets the request through when its bearer token belongs to
+// userID, and replies 401 or 403 otherwise.
+func requireOwner(w http.ResponseWriter, r *http.Request, userID uuid.UUID) bool {
+	viewer, ok := requireUser(w, r)
+	if !ok {
+		return false
+	}
+	if viewer != userID {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return false
+	}
+	return true
+}
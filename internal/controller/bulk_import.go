@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/satyam-svg/resume-parser/internal/service"
+)
+
+// maxArchiveSize caps an uploaded ZIP of resumes
+const maxArchiveSize = 200 << 20
+
+type BulkImportController struct {
+	Service *service.BulkImportService
+	Tenants *service.TenantService
+}
+
+// ImportResumes accepts a ZIP of resumes and starts parsing them:
+// POST /jobs/recruiter/{recruiterID}/imports (multipart "archive") with the
+// recruiter's "Authorization: Bearer <token>". It replies 202 with the import to poll for progress. PII redaction follows
+// the settings of the X-Tenant-ID tenant if the recruiter may act for it, or
+// else of the recruiter.
+func (bc *BulkImportController) ImportResumes(w http.ResponseWriter, r *http.Request, recruiterID string) {
+	rid, err := uuid.Parse(recruiterID)
+	if err != nil {
+		http.Error(w, "Invalid recruiter ID", http.StatusBadRequest)
+		return
+	}
+	if !requireOwner(w, r, rid) {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxArchiveSize)
+	file, header, err := r.FormFile("archive")
+	if err != nil {
+		http.Error(w, "Error reading archive", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	tmp, err := os.CreateTemp("", "import-*.zip")
+	if err != nil {
+		http.Error(w, "Failed to save archive", http.StatusInternalServerError)
+		return
+	}
+	_, err = io.Copy(tmp, file)
+	tmp.Close()
+	if err != nil {
+		os.Remove(tmp.Name())
+		http.Error(w, "Failed to save archive", http.StatusInternalServerError)
+		return
+	}
+
+	opts := bc.Tenants.ParseOptions(bc.Tenants.TenantFor(rid, r.Header.Get("X-Tenant-ID")), "")
+
+	imp, err := bc.Service.Start(rid, tmp.Name(), header.Filename, opts.RedactPII)
+	switch {
+	case errors.Is(err, service.ErrRecruiterNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, service.ErrInvalidArchive), errors.Is(err, service.ErrEmptyArchive):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, service.ErrTooManyFiles):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	case err != nil:
+		http.Error(w, "Failed to start import", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(imp)
+}
+
+// GetImport reports an import's progress and per-file results:
+// GET /jobs/recruiter/{recruiterID}/imports/{importID} with the recruiter's
+// "Authorization: Bearer <token>"
+func (bc *BulkImportController) GetImport(w http.ResponseWriter, r *http.Request, recruiterID, importID string) {
+	rid, err := uuid.Parse(recruiterID)
+	if err != nil {
+		http.Error(w, "Invalid recruiter ID", http.StatusBadRequest)
+		return
+	}
+	if !requireOwner(w, r, rid) {
+		return
+	}
+	id, err := strconv.ParseUint(importID, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid import ID", http.StatusBadRequest)
+		return
+	}
+
+	imp, err := bc.Service.Get(uint(id))
+	if errors.Is(err, service.ErrImportNotFound) || (err == nil && imp.RecruiterID != rid) {
+		http.Error(w, "Import not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to load import", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(imp)
+}
+
+// GetTalentPool lists the recruiter's candidates: GET /jobs/recruiter/{recruiterID}/talent-pool
+// with the recruiter's "Authorization: Bearer <token>"
+func (bc *BulkImportController) GetTalentPool(w http.ResponseWriter, r *http.Request, recruiterID string) {
+	rid, err := uuid.Parse(recruiterID)
+	if err != nil {
+		http.Error(w, "Invalid recruiter ID", http.StatusBadRequest)
+		return
+	}
+	if !requireOwner(w, r, rid) {
+		return
+	}
+
+	entries, err := bc.Service.TalentPool(rid)
+	if err != nil {
+		http.Error(w, "Failed to load talent pool", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"recruiter_id": rid,
+		"count":        len(entries),
+		"candidates":   entries,
+	})
+}
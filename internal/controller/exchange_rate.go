@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/service"
+)
+
+type ExchangeRateController struct {
+	Service *service.ExchangeRateService
+}
+
+// GetExchangeRates lists the locally maintained currency and token rates
+func (ec *ExchangeRateController) GetExchangeRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := ec.Service.List()
+	if err != nil {
+		http.Error(w, "Failed to fetch exchange rates", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rates)
+}
+
+// UpsertExchangeRate creates or updates a rate (admin only): {"currency": "ETH", "usd_per_unit": 2500, "is_crypto": true}
+func (ec *ExchangeRateController) UpsertExchangeRate(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	var rate model.ExchangeRate
+	if err := json.NewDecoder(r.Body).Decode(&rate); err != nil {
+		http.Error(w, "Invalid input", http.StatusBadRequest)
+		return
+	}
+
+	if err := ec.Service.Upsert(&rate); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rate)
+}
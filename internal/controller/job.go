@@ -3,19 +3,26 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/satyam-svg/resume-parser/internal/model"
 	"github.com/satyam-svg/resume-parser/internal/service"
 	"github.com/satyam-svg/resume-parser/internal/utils"
 )
 
 type JobController struct {
-	Service *service.JobService
+	Service   *service.JobService
+	Analytics *service.AnalyticsService
+	Rates     *service.ExchangeRateService
+
+	Applications *service.ApplicationService
 }
 
 // Create a new job
@@ -27,6 +34,16 @@ func (jc *JobController) PostJob(w http.ResponseWriter, r *http.Request) {
 	}
 	job.CreatedAt = time.Now()
 
+	rates, err := jc.Rates.Rates()
+	if err != nil {
+		http.Error(w, "Failed to load exchange rates", http.StatusInternalServerError)
+		return
+	}
+	if err := service.PrepareJob(&job, rates); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	if err := jc.Service.CreateJob(&job); err != nil {
 		http.Error(w, "Failed to create job", http.StatusInternalServerError)
 		return
@@ -34,16 +51,151 @@ func (jc *JobController) PostJob(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(job)
 }
 
-// Get all jobs
+// PublishJob publishes a draft job: POST /jobs/{jobID}/publish with
+// "Authorization: Bearer <token>" of the job's recruiter. An optional JSON
+// body of job fields applies the recruiter's corrections first.
+func (jc *JobController) PublishJob(w http.ResponseWriter, r *http.Request, id string) {
+	recruiterID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	jobID, err := strconv.Atoi(id)
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+	job, err := jc.Service.GetJobByID(uint(jobID))
+	if err != nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+	if job.RecruiterID != recruiterID {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	draft := *job
+	if err := json.NewDecoder(r.Body).Decode(&draft); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Corrections cannot move the job to another recruiter or change its history
+	draft.ID, draft.RecruiterID, draft.Status, draft.CreatedAt = job.ID, job.RecruiterID, job.Status, job.CreatedAt
+
+	rates, err := jc.Rates.Rates()
+	if err != nil {
+		http.Error(w, "Failed to load exchange rates", http.StatusInternalServerError)
+		return
+	}
+	if err := service.PrepareJob(&draft, rates); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = jc.Service.PublishJob(&draft)
+	switch {
+	case errors.Is(err, service.ErrJobAlreadyPublished):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, "Failed to publish job", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(draft)
+}
+
+// Get all jobs, optionally filtered by location and salary:
+// ?near=Bengaluru&radius_km=25 | ?lat=..&lng=..&radius_km=.. | ?remote=true | ?work_mode=hybrid
+// ?currency=EUR&salary_min=80000&salary_max=120000 (annual amounts in currency)
 func (jc *JobController) GetJobs(w http.ResponseWriter, r *http.Request) {
-	jobs, err := jc.Service.GetJobs()
+	filter, err := parseJobFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	jobs, err := jc.Service.SearchJobs(filter)
+	if errors.Is(err, service.ErrUnknownCurrency) {
+		http.Error(w, "Unsupported currency: "+filter.Currency, http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed to fetch jobs", http.StatusInternalServerError)
 		return
 	}
+
+	for _, job := range jobs {
+		jc.recordEvent(r, job.ID, model.EventView)
+	}
+
 	json.NewEncoder(w).Encode(jobs)
 }
 
+func parseJobFilter(r *http.Request) (service.JobFilter, error) {
+	q := r.URL.Query()
+	filter := service.JobFilter{
+		RemoteOnly:    q.Get("remote") == "true",
+		IncludeRemote: q.Get("include_remote") == "true",
+	}
+
+	if mode := q.Get("work_mode"); mode != "" {
+		switch mode {
+		case model.WorkModeOnsite, model.WorkModeHybrid, model.WorkModeRemote:
+			filter.WorkMode = mode
+		default:
+			return filter, fmt.Errorf("Invalid work_mode. Must be onsite, hybrid or remote")
+		}
+	}
+
+	if v := q.Get("radius_km"); v != "" {
+		radius, err := strconv.ParseFloat(v, 64)
+		if err != nil || radius <= 0 {
+			return filter, fmt.Errorf("Invalid radius_km")
+		}
+		filter.RadiusKm = radius
+	}
+
+	switch {
+	case q.Get("near") != "":
+		loc := utils.ResolveLocation(q.Get("near"))
+		if !loc.HasCoordinates() {
+			return filter, fmt.Errorf("Unknown location: %s", q.Get("near"))
+		}
+		filter.Near = &loc
+	case q.Get("lat") != "" || q.Get("lng") != "":
+		lat, errLat := strconv.ParseFloat(q.Get("lat"), 64)
+		lng, errLng := strconv.ParseFloat(q.Get("lng"), 64)
+		if errLat != nil || errLng != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+			return filter, fmt.Errorf("Invalid lat/lng")
+		}
+		filter.Near = &model.Location{Latitude: lat, Longitude: lng, Precision: model.PrecisionCity}
+	}
+
+	for key, dst := range map[string]*float64{"salary_min": &filter.SalaryMin, "salary_max": &filter.SalaryMax} {
+		if v := q.Get(key); v != "" {
+			amount, err := strconv.ParseFloat(v, 64)
+			if err != nil || amount < 0 {
+				return filter, fmt.Errorf("Invalid %s", key)
+			}
+			*dst = amount
+		}
+	}
+	filter.Currency = strings.ToUpper(q.Get("currency"))
+	if filter.Currency == "" && (filter.SalaryMin > 0 || filter.SalaryMax > 0) {
+		filter.Currency = "USD"
+	}
+
+	if filter.RadiusKm > 0 && filter.Near == nil {
+		return filter, fmt.Errorf("radius_km requires near or lat/lng")
+	}
+	if filter.Near != nil && filter.RadiusKm == 0 {
+		filter.RadiusKm = 50
+	}
+
+	return filter, nil
+}
+
 // Get jobs by recruiter ID
 func (jc *JobController) GetJobsByRecruiterID(w http.ResponseWriter, r *http.Request, recruiterID string) {
 	jobs, err := jc.Service.GetJobsByRecruiter(recruiterID)
@@ -88,7 +240,13 @@ func (jc *JobController) GetAISuggestions(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	prompt := buildPrompt(*job, users)
+	screening, err := jc.Applications.ScreeningSummaries(job.ID)
+	if err != nil {
+		http.Error(w, "Failed to fetch screening answers", http.StatusInternalServerError)
+		return
+	}
+
+	prompt := buildPrompt(*job, users, screening)
 	matchResult, err := utils.CallGemini(prompt)
 	if err != nil {
 		http.Error(w, "Gemini failed", http.StatusInternalServerError)
@@ -98,8 +256,8 @@ func (jc *JobController) GetAISuggestions(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode(matchResult)
 }
 
-// Prompt Builder
-func buildPrompt(job model.Job, users []model.User) string {
+// Prompt Builder. screening holds each applicant's screening answers, if they applied.
+func buildPrompt(job model.Job, users []model.User, screening map[uuid.UUID]string) string {
 	prompt := fmt.Sprintf(`You are an AI recruitment assistant.
 Analyze the job below and match it with the best candidates.
 
@@ -110,8 +268,11 @@ Location: %s
 Tags: %s
 Description: %s
 
+Candidates who answered the job's screening questions list them under "Screening Answers";
+answers marked (knockout) fail a hard requirement set by the recruiter.
+
 Candidates:
-`, job.Title, job.Company, job.Location, job.Tags, job.Description)
+`, job.Title, job.Company, utils.FormatLocation(job.Geo, job.Location), job.Tags, job.Description)
 
 	for _, u := range users {
 		prompt += fmt.Sprintf(`
@@ -120,7 +281,10 @@ Email: %s
 Location: %s
 Skills: %s
 Experience: %s
-`, u.FullName, u.Email, u.Location, u.Skills, u.Experience)
+`, u.FullName, u.Email, utils.FormatLocation(u.Geo, u.Location), u.Skills, formatExperience(u.Experience))
+		if answers, ok := screening[u.ID]; ok && answers != "" {
+			prompt += fmt.Sprintf("Screening Answers: %s\n", answers)
+		}
 	}
 
 	prompt += `
@@ -141,6 +305,24 @@ Respond only with a valid JSON in the following format:
 	return prompt
 }
 
+// formatExperience lists positions for a prompt, followed by the net years of
+// experience.
+func formatExperience(experience []model.Experience) string {
+	var parts []string
+	for _, e := range experience {
+		part := e.Title
+		if e.Company != "" {
+			part = strings.TrimSpace(part + " at " + e.Company)
+		}
+		if e.Years != "" {
+			part += " (" + e.Years + ")"
+		}
+		parts = append(parts, part)
+	}
+	summary := utils.SummarizeExperience(experience, time.Now())
+	return fmt.Sprintf("%s (%.1f years in total)", strings.Join(parts, "; "), summary.NetYears)
+}
+
 // Get a single job by ID
 func (jc *JobController) GetJobByID(w http.ResponseWriter, r *http.Request, id string) {
 	jobID, err := strconv.Atoi(id)
@@ -155,6 +337,8 @@ func (jc *JobController) GetJobByID(w http.ResponseWriter, r *http.Request, id s
 		return
 	}
 
+	jc.recordEvent(r, job.ID, model.EventDetailOpen)
+
 	json.NewEncoder(w).Encode(job)
 }
 
@@ -189,7 +373,7 @@ Skills: %s
 Experience: %s
 
 Jobs:
-`, user.FullName, user.Email, user.Location, user.Skills, user.Experience)
+`, user.FullName, user.Email, utils.FormatLocation(user.Geo, user.Location), user.Skills, formatExperience(user.Experience))
 
 	for _, job := range jobs {
 		prompt += fmt.Sprintf(`
@@ -198,7 +382,7 @@ Company: %s
 Location: %s
 Tags: %s
 Description: %s
-`, job.Title, job.Company, job.Location, job.Tags, job.Description)
+`, job.Title, job.Company, utils.FormatLocation(job.Geo, job.Location), job.Tags, job.Description)
 	}
 
 	prompt += `
@@ -0,0 +1,18 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/satyam-svg/resume-parser/internal/service"
+)
+
+// GetParseCacheMetrics reports parse cache hits and misses (admin only):
+// GET /admin/parse-cache
+func GetParseCacheMetrics(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	io.WriteString(w, service.ParseCacheMetrics())
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/satyam-svg/resume-parser/internal/service"
+	"github.com/satyam-svg/resume-parser/internal/utils"
+)
+
+type ReferralController struct {
+	Service *service.ReferralService
+	Jobs    *service.JobService
+}
+
+// CreateReferral returns the referrer's code for a job: POST /referrals {"job_id"}
+// with the referrer's "Authorization: Bearer <token>".
+func (rc *ReferralController) CreateReferral(w http.ResponseWriter, r *http.Request) {
+	referrerID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		JobID uint `json:"job_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid input", http.StatusBadRequest)
+		return
+	}
+
+	user, err := rc.Jobs.GetUserByID(referrerID.String())
+	if err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+	if _, err := rc.Jobs.GetJobByID(body.JobID); err != nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	ref, err := rc.Service.GetOrCreate(user.ID, body.JobID)
+	switch {
+	case errors.Is(err, service.ErrRecruiterReferral):
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	case err != nil:
+		http.Error(w, "Failed to create referral", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":   ref.Code,
+		"job_id": ref.JobID,
+		"link":   "/referrals/" + ref.Code,
+	})
+}
+
+// OpenReferral records a click on a referral link and returns the job:
+// GET /referrals/{code}. Pass the code as referral_code when applying.
+func (rc *ReferralController) OpenReferral(w http.ResponseWriter, r *http.Request, code string) {
+	referral, err := rc.Service.GetByCode(code)
+	if errors.Is(err, service.ErrReferralNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to open referral", http.StatusInternalServerError)
+		return
+	}
+
+	// Crawlers unfurling links shared on Discord are not clicks
+	if !service.IsBot(r.UserAgent()) {
+		visitor := service.VisitorID(utils.ClientIP(r), r.UserAgent())
+		if err := rc.Service.RecordClick(referral, visitor); err != nil {
+			http.Error(w, "Failed to record referral click", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"referral_code": referral.Code,
+		"job":           referral.Job,
+	})
+}
+
+// GetReferralDashboard summarizes a user's referrals: GET /users/{userID}/referrals
+func (rc *ReferralController) GetReferralDashboard(w http.ResponseWriter, r *http.Request, userID string) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	dash, err := rc.Service.Dashboard(id)
+	if err != nil {
+		http.Error(w, "Failed to fetch referrals", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dash)
+}
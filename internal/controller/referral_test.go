@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/service"
+	"github.com/satyam-svg/resume-parser/internal/utils"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB opens an empty SQLite database with the job and referral tables.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&model.User{}, &model.Job{}, &model.JobApplication{}, &model.ScreeningQuestion{},
+		&model.ApplicationAnswer{}, &model.Referral{}, &model.ReferralClick{}, &model.ReferralReward{}, &model.Resume{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// createUser stores a user with the given email and role.
+func createUser(t *testing.T, db *gorm.DB, email, role string) model.User {
+	t.Helper()
+	user := model.User{Email: email, Role: role}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+// serve calls handler with a JSON body and, unless token is empty, a bearer token.
+func serve(handler func(http.ResponseWriter, *http.Request), method, path, token, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return w
+}
+
+type referralFixture struct {
+	db                            *gorm.DB
+	recruiter, referrer, intruder model.User
+	job                           model.Job
+	referrals                     *ReferralController
+	applications                  *ApplicationController
+}
+
+func newReferralFixture(t *testing.T) *referralFixture {
+	t.Helper()
+	db := newTestDB(t)
+	f := &referralFixture{
+		db:        db,
+		recruiter: createUser(t, db, "recruiter@example.com", "recruiter"),
+		referrer:  createUser(t, db, "referrer@example.com", "applicant"),
+		intruder:  createUser(t, db, "intruder@example.com", "applicant"),
+	}
+	f.job = model.Job{Title: "Backend Engineer", RecruiterID: f.recruiter.ID}
+	if err := db.Create(&f.job).Error; err != nil {
+		t.Fatal(err)
+	}
+	jobs := &service.JobService{DB: db}
+	referrals := &service.ReferralService{DB: db, RewardStage: model.StageHired, RewardCredits: 10}
+	f.referrals = &ReferralController{Service: referrals, Jobs: jobs}
+	f.applications = &ApplicationController{Service: &service.ApplicationService{DB: db, Referrals: referrals}, Jobs: jobs}
+	return f
+}
+
+func TestCreateReferralTakesReferrerFromToken(t *testing.T) {
+	f := newReferralFixture(t)
+	// The body names another user; the code must still be the token's user's
+	body := `{"user_id":"` + f.intruder.ID.String() + `","job_id":` + strconv.FormatUint(uint64(f.job.ID), 10) + `}`
+
+	if w := serve(f.referrals.CreateReferral, "POST", "/referrals", "", body); w.Code != http.StatusUnauthorized {
+		t.Errorf("without a token: %d, want 401", w.Code)
+	}
+
+	w := serve(f.referrals.CreateReferral, "POST", "/referrals", utils.GenerateJWT(f.referrer.ID), body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("with the referrer's token: %d %s", w.Code, w.Body)
+	}
+	var ref model.Referral
+	if err := f.db.First(&ref).Error; err != nil {
+		t.Fatal(err)
+	}
+	if ref.ReferrerID != f.referrer.ID {
+		t.Errorf("the code belongs to %s, want the token's user %s", ref.ReferrerID, f.referrer.ID)
+	}
+
+	if w := serve(f.referrals.CreateReferral, "POST", "/referrals", utils.GenerateJWT(f.recruiter.ID), body); w.Code != http.StatusForbidden {
+		t.Errorf("with the job's recruiter's token: %d, want 403", w.Code)
+	}
+}
+
+func TestApplyTakesApplicantFromToken(t *testing.T) {
+	f := newReferralFixture(t)
+	jobID := strconv.FormatUint(uint64(f.job.ID), 10)
+	path := "/jobs/" + jobID + "/apply"
+	// The referrer tries to claim their own code by applying in the name of someone else
+	ref, err := f.referrals.Service.GetOrCreate(f.referrer.ID, f.job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := `{"user_id":"` + f.intruder.ID.String() + `","referral_code":"` + ref.Code + `"}`
+	apply := func(w http.ResponseWriter, r *http.Request) { f.applications.Apply(w, r, jobID) }
+
+	if w := serve(apply, "POST", path, "", body); w.Code != http.StatusUnauthorized {
+		t.Errorf("without a token: %d, want 401", w.Code)
+	}
+	if w := serve(apply, "POST", path, utils.GenerateJWT(f.referrer.ID), body); w.Code != http.StatusBadRequest {
+		t.Errorf("applying with your own code: %d %s, want 400", w.Code, w.Body)
+	}
+	var count int64
+	f.db.Model(&model.JobApplication{}).Where("user_id = ?", f.intruder.ID).Count(&count)
+	if count != 0 {
+		t.Errorf("an application was stored for the user named in the body")
+	}
+}
@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/service"
+)
+
+const (
+	defaultUnknownSkillsLimit = 100
+	maxUnknownSkillsLimit     = 1000
+)
+
+type SkillController struct {
+	Service *service.SkillService
+}
+
+// ListSkills returns the skill taxonomy: GET /skills?category=framework
+func (sc *SkillController) ListSkills(w http.ResponseWriter, r *http.Request) {
+	skills, err := sc.Service.List(r.URL.Query().Get("category"))
+	if err != nil {
+		http.Error(w, "Failed to fetch skills", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"skills": skills,
+	})
+}
+
+// GetSkill looks a skill up by its name or an alias: GET /skills/{name}
+func (sc *SkillController) GetSkill(w http.ResponseWriter, r *http.Request, name string) {
+	skill, err := sc.Service.Get(name)
+	if errors.Is(err, service.ErrSkillNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to fetch skill", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(skill)
+}
+
+// UpsertSkill creates or replaces a skill (admin only):
+// PUT /admin/skills/{name} {"category": "framework", "parent": "JavaScript", "aliases": ["reactjs"]}
+func (sc *SkillController) UpsertSkill(w http.ResponseWriter, r *http.Request, name string) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	var skill model.Skill
+	if err := json.NewDecoder(r.Body).Decode(&skill); err != nil {
+		http.Error(w, "Invalid input", http.StatusBadRequest)
+		return
+	}
+	skill.Name = name
+
+	err := sc.Service.Upsert(&skill)
+	switch {
+	case errors.Is(err, service.ErrInvalidSkill):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, service.ErrSkillConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, "Failed to save skill", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(skill)
+}
+
+// DeleteSkill removes a skill (admin only): DELETE /admin/skills/{name}
+func (sc *SkillController) DeleteSkill(w http.ResponseWriter, r *http.Request, name string) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	err := sc.Service.Delete(name)
+	if errors.Is(err, service.ErrSkillNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to delete skill", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListUnknownSkills reports skills and tags not in the taxonomy, the most
+// frequent first (admin only): GET /admin/unknown-skills?limit=100
+func (sc *SkillController) ListUnknownSkills(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	limit := defaultUnknownSkillsLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxUnknownSkillsLimit {
+			http.Error(w, "limit must be between 1 and 1000", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	unknown, err := sc.Service.Unknown(limit)
+	if err != nil {
+		http.Error(w, "Failed to fetch unknown skills", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"unknown_skills": unknown,
+	})
+}
+
+// DismissUnknownSkill drops a skill from the report without adding it to the
+// taxonomy (admin only): DELETE /admin/unknown-skills/{name}
+func (sc *SkillController) DismissUnknownSkill(w http.ResponseWriter, r *http.Request, name string) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	err := sc.Service.DismissUnknown(name)
+	if errors.Is(err, service.ErrSkillNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to dismiss skill", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/service"
+)
+
+type TenantController struct {
+	Service *service.TenantService
+}
+
+// GetSettings returns a tenant's settings: GET /tenants/{tenantID}/settings
+func (tc *TenantController) GetSettings(w http.ResponseWriter, r *http.Request, tenantID string) {
+	settings, err := tc.Service.Settings(tenantID)
+	if err != nil {
+		http.Error(w, "Failed to load settings", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// UpdateSettings replaces a tenant's settings: PUT /tenants/{tenantID}/settings {"redact_pii": true}
+// with "Authorization: Bearer <token>" of an admin or of the tenant's owner,
+// the user whose ID is the tenant ID.
+func (tc *TenantController) UpdateSettings(w http.ResponseWriter, r *http.Request, tenantID string) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	if userID.String() != tenantID && !isAdmin(userID) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	var settings model.TenantSettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		http.Error(w, "Invalid input", http.StatusBadRequest)
+		return
+	}
+	settings.TenantID = tenantID
+
+	if err := tc.Service.UpdateSettings(&settings); err != nil {
+		http.Error(w, "Failed to save settings", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
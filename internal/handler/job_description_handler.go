@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/satyam-svg/resume-parser/internal/service"
+)
+
+// JobDescriptionHandler turns uploaded job descriptions into draft jobs.
+type JobDescriptionHandler struct {
+	Jobs    *service.JobService
+	Rates   *service.ExchangeRateService
+	Tenants *service.TenantService
+}
+
+// Parse reads a job description and saves it as a draft job of the
+// recruiter: POST /jobs/parse?recruiter_id= with the recruiter's bearer
+// token and the file in the multipart "document" field, or as the body. It
+// replies 201 with the draft, which the recruiter reviews and publishes with
+// POST /jobs/{jobID}/publish. ?mode= picks the parser as for resumes.
+func (h *JobDescriptionHandler) Parse(w http.ResponseWriter, r *http.Request) {
+	recruiterID, err := uuid.Parse(r.URL.Query().Get("recruiter_id"))
+	if err != nil {
+		http.Error(w, "Invalid recruiter ID", http.StatusBadRequest)
+		return
+	}
+	if !requireOwner(w, r, recruiterID) {
+		return
+	}
+	_, err = h.Jobs.GetRecruiter(recruiterID)
+	switch {
+	case errors.Is(err, service.ErrRecruiterNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, "Failed to load recruiter", http.StatusInternalServerError)
+		return
+	}
+
+	opts := parseOptions(r, h.Tenants)
+	if err := service.ValidateParserMode(opts.Mode); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	path, _, status, err := saveUploadedDocument(w, r, "document", "Job description")
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	defer os.Remove(path)
+
+	result, err := service.ParseJobDescription(path, opts)
+	if err != nil {
+		http.Error(w, "Failed to parse job description: "+err.Error(), parseErrorStatus(err))
+		return
+	}
+
+	rates, err := h.Rates.Rates()
+	if err != nil {
+		http.Error(w, "Failed to load exchange rates", http.StatusInternalServerError)
+		return
+	}
+	if err := h.Jobs.CreateDraft(recruiterID, result, rates); err != nil {
+		log.Printf("❌ Saving draft job for recruiter %s failed: %v", recruiterID, err)
+		http.Error(w, "Failed to save draft job", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
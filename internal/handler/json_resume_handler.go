@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/satyam-svg/resume-parser/internal/service"
+	"gorm.io/gorm"
+)
+
+// maxJSONResumeSize bounds an imported JSON Resume document
+const maxJSONResumeSize = 1 << 20
+
+// JSONResumeHandler exports and imports profiles as JSON Resume
+// (jsonresume.org) documents.
+type JSONResumeHandler struct {
+	Profiles *service.ProfileService
+}
+
+// Export returns the profile as a JSON Resume: GET /user/{id}/resume.json
+func (h *JSONResumeHandler) Export(w http.ResponseWriter, r *http.Request, userID string) {
+	user, err := h.Profiles.GetUser(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to load user", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(service.ExportJSONResume(user))
+}
+
+// Import saves a JSON Resume onto the profile and returns the profile as
+// exported afterwards: PUT /user/{id}/resume.json
+func (h *JSONResumeHandler) Import(w http.ResponseWriter, r *http.Request, userID string) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	if !requireOwner(w, r, uid) {
+		return
+	}
+
+	var doc map[string]interface{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxJSONResumeSize)).Decode(&doc); err != nil || doc == nil {
+		http.Error(w, "Invalid input: expected a JSON Resume object", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.Profiles.ImportJSONResume(userID, doc)
+	switch {
+	case errors.Is(err, service.ErrInvalidJSONResume):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, "Failed to import resume", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(service.ExportJSONResume(user))
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/service"
+	"gorm.io/gorm"
+)
+
+// ResumeApplyHandler parses a resume and applies it to a user's profile.
+type ResumeApplyHandler struct {
+	Profiles *service.ProfileService
+	Tenants  *service.TenantService
+}
+
+type ApplyResumeRequest struct {
+	Parsed   model.ParsedResume `json:"parsed"`
+	Choices  model.MergeChoices `json:"choices"`
+	Language string             `json:"language"` // as detected by the preview; stored on the profile
+}
+
+// Preview parses an uploaded resume and diffs it against the profile without
+// saving anything: POST /user/{id}/resume/preview (multipart "resume") with
+// the owner's "Authorization: Bearer <token>".
+func (h *ResumeApplyHandler) Preview(w http.ResponseWriter, r *http.Request, userID string) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	if !requireOwner(w, r, uid) {
+		return
+	}
+
+	user, err := h.Profiles.GetUser(userID)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	path, _, status, err := saveUploadedResume(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	defer os.Remove(path)
+
+	result, err := service.ParseResume(path, parseOptions(r, h.Tenants))
+	if err != nil {
+		http.Error(w, "Failed to parse resume: "+err.Error(), parseErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"schema_version": result.SchemaVersion,
+		"parsed":         result.Resume,
+		"fields":         result.Fields,
+		"low_confidence": result.LowConfidence,
+		"language":       result.Language,
+		"preview":        h.Profiles.Preview(user, result.Resume),
+	})
+}
+
+// Apply saves a previewed resume onto the profile with a merge, replace or
+// skip choice per section: POST /user/{id}/resume/apply with the owner's
+// "Authorization: Bearer <token>".
+func (h *ResumeApplyHandler) Apply(w http.ResponseWriter, r *http.Request, userID string) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	if !requireOwner(w, r, uid) {
+		return
+	}
+
+	var input ApplyResumeRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid input", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.Profiles.Apply(userID, &input.Parsed, input.Language, input.Choices)
+	switch {
+	case errors.Is(err, service.ErrInvalidMergeChoice), errors.Is(err, service.ErrUnknownLanguage):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, "Failed to apply resume", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Profile updated from resume",
+		"user":    user,
+	})
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/satyam-svg/resume-parser/internal/utils"
+)
+
+func TestResumeApplyRequiresOwner(t *testing.T) {
+	// No services: the owner check must reply before the upload or body is read
+	h := &ResumeApplyHandler{}
+	owner, other := uuid.New(), uuid.New()
+
+	for _, tt := range []struct {
+		name   string
+		serve  func(w http.ResponseWriter, r *http.Request, userID string)
+		body   string
+		token  string
+		userID string
+		want   int
+	}{
+		{"preview without a token", h.Preview, "", "", owner.String(), http.StatusUnauthorized},
+		{"preview with another user's token", h.Preview, "", utils.GenerateJWT(other), owner.String(), http.StatusForbidden},
+		{"preview of an invalid user ID", h.Preview, "", utils.GenerateJWT(owner), "not-a-uuid", http.StatusBadRequest},
+		{"apply without a token", h.Apply, `{"parsed":{"full_name":"Mallory"}}`, "", owner.String(), http.StatusUnauthorized},
+		{"apply with another user's token", h.Apply, `{"parsed":{"full_name":"Mallory"}}`, utils.GenerateJWT(other), owner.String(), http.StatusForbidden},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/user/"+tt.userID+"/resume", strings.NewReader(tt.body))
+			if tt.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			tt.serve(w, r, tt.userID)
+			if w.Code != tt.want {
+				t.Errorf("got %d %q, want %d", w.Code, strings.TrimSpace(w.Body.String()), tt.want)
+			}
+		})
+	}
+}
@@ -1,49 +1,293 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/satyam-svg/resume-parser/config"
+	"github.com/satyam-svg/resume-parser/internal/model"
 	"github.com/satyam-svg/resume-parser/internal/service"
+	"github.com/satyam-svg/resume-parser/internal/utils"
 )
 
-func UploadResumeHandler(w http.ResponseWriter, r *http.Request) {
+// Longest a status request may block with ?wait=
+const maxParseWait = 60 * time.Second
+
+// maxResumeSize caps an uploaded resume; a multipart body may be a little
+// larger for its headers.
+const (
+	maxResumeSize      = 10 << 20
+	maxMultipartHeader = 64 << 10
+)
+
+// ResumeUploadHandler queues uploaded resumes for background parsing and
+// reports on the parse jobs.
+type ResumeUploadHandler struct {
+	Jobs    *service.ParseJobService
+	Tenants *service.TenantService
+}
+
+// Upload saves the resume and queues it: POST /upload replies 202 with the
+// job to poll. With ?sync=true it parses within the request instead.
+func (h *ResumeUploadHandler) Upload(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Only POST method is supported", http.StatusMethodNotAllowed)
 		return
 	}
 
-	file, _, err := r.FormFile("resume")
-	if err != nil {
-		http.Error(w, "Error reading file", http.StatusBadRequest)
+	opts := parseOptions(r, h.Tenants)
+	if err := service.ValidateParserMode(opts.Mode); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	defer file.Close()
 
-	tempFile, err := os.CreateTemp("", "resume-*.pdf")
+	path, _, status, err := saveUploadedResume(w, r)
 	if err != nil {
-		http.Error(w, "Failed to create temp file", http.StatusInternalServerError)
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	if sync, _ := strconv.ParseBool(r.URL.Query().Get("sync")); sync {
+		defer os.Remove(path)
+		result, err := service.ParseResume(path, opts)
+		if err != nil {
+			http.Error(w, "Failed to parse resume: "+err.Error(), parseErrorStatus(err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
 		return
 	}
-	defer os.Remove(tempFile.Name())
 
-	_, err = io.Copy(tempFile, file)
+	job, err := h.Jobs.Enqueue(path, opts)
 	if err != nil {
-		http.Error(w, "Failed to save file", http.StatusInternalServerError)
+		os.Remove(path)
+		http.Error(w, "Failed to queue resume", http.StatusInternalServerError)
 		return
 	}
 
-	tempFile.Close()
+	statusURL := "/upload/jobs/" + job.ID.String()
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", statusURL)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"job_id":     job.ID,
+		"status":     job.Status,
+		"status_url": statusURL,
+		"events_url": statusURL + "/events",
+	})
+}
 
-	// Call the Gemini service
-	jsonOutput, err := service.ParseResume(tempFile.Name())
+// Status reports a parse job: GET /upload/jobs/{id}. With ?wait=N it blocks
+// up to N seconds for the job to finish (long polling).
+func (h *ResumeUploadHandler) Status(w http.ResponseWriter, r *http.Request, jobID string) {
+	id, err := uuid.Parse(jobID)
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	var job *model.ParseJob
+	if wait, _ := strconv.Atoi(r.URL.Query().Get("wait")); wait > 0 {
+		ctx, cancel := context.WithTimeout(r.Context(), min(time.Duration(wait)*time.Second, maxParseWait))
+		defer cancel()
+		job, err = h.Jobs.Wait(ctx, id)
+	} else {
+		job, err = h.Jobs.Get(id)
+	}
+	if errors.Is(err, service.ErrParseJobNotFound) {
+		http.Error(w, "Parse job not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
-		http.Error(w, "Failed to parse resume: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Failed to load parse job", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	fmt.Fprint(w, jsonOutput)
+	json.NewEncoder(w).Encode(job)
+}
+
+// Events streams a parse job as server-sent events: GET /upload/jobs/{id}/events
+// sends a "status" event right away and a "done" event when the job finishes.
+func (h *ResumeUploadHandler) Events(w http.ResponseWriter, r *http.Request, jobID string) {
+	id, err := uuid.Parse(jobID)
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	job, err := h.Jobs.Get(id)
+	if errors.Is(err, service.ErrParseJobNotFound) {
+		http.Error(w, "Parse job not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to load parse job", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	send := func(event string, job *model.ParseJob) {
+		data, _ := json.Marshal(job)
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+		flusher.Flush()
+	}
+
+	send("status", job)
+	// Comments keep proxies from closing an idle stream
+	for !job.Done() {
+		ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+		job, err = h.Jobs.Wait(ctx, id)
+		cancel()
+		if err != nil || r.Context().Err() != nil {
+			return
+		}
+		if !job.Done() {
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		}
+	}
+	send("done", job)
+}
+
+// ResumeSchemaHandler serves the JSON Schema of the parsed resume returned by /upload
+func ResumeSchemaHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.Write(service.ParsedResumeSchema)
+}
+
+// saveUploadedResume copies the uploaded resume to a temp file and returns
+// its path and original name, or an error message with the HTTP status to
+// reply with. Multipart requests carry the file in the "resume" field; any
+// other body, such as pasted text or Markdown, is taken as the resume itself
+// and named by ?filename=. The caller removes the file.
+func saveUploadedResume(w http.ResponseWriter, r *http.Request) (string, string, int, error) {
+	return saveUploadedDocument(w, r, "resume", "Resume")
+}
+
+// saveUploadedDocument saves an upload carried in the multipart field, or
+// as the whole body, to a temp file. Files over maxResumeSize, and files
+// whose content is not a supported format whatever their name or declared
+// type, are refused before anything parses them; label names the document
+// in error messages.
+func saveUploadedDocument(w http.ResponseWriter, r *http.Request, field, label string) (string, string, int, error) {
+	tooLarge := fmt.Errorf("%s is larger than %d MB", label, maxResumeSize>>20)
+	r.Body = http.MaxBytesReader(w, r.Body, maxResumeSize+maxMultipartHeader)
+
+	var src io.Reader
+	name := r.URL.Query().Get("filename")
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, header, err := r.FormFile(field)
+		if err != nil {
+			if isBodyTooLarge(err) {
+				return "", "", http.StatusRequestEntityTooLarge, tooLarge
+			}
+			return "", "", http.StatusBadRequest, fmt.Errorf("Error reading file")
+		}
+		defer file.Close()
+		src, name = file, header.Filename
+	} else {
+		src = r.Body
+	}
+	if name == "" {
+		name = field
+	}
+
+	// The format is sniffed from the content later, so no extension is implied
+	tempFile, err := os.CreateTemp("", field+"-*")
+	if err != nil {
+		return "", "", http.StatusInternalServerError, fmt.Errorf("Failed to create temp file")
+	}
+	defer tempFile.Close()
+
+	n, err := io.Copy(tempFile, io.LimitReader(src, maxResumeSize+1))
+	if err != nil {
+		os.Remove(tempFile.Name())
+		if isBodyTooLarge(err) {
+			return "", "", http.StatusRequestEntityTooLarge, tooLarge
+		}
+		return "", "", http.StatusInternalServerError, fmt.Errorf("Failed to save file")
+	}
+	if n > maxResumeSize {
+		os.Remove(tempFile.Name())
+		return "", "", http.StatusRequestEntityTooLarge, tooLarge
+	}
+	if n == 0 {
+		os.Remove(tempFile.Name())
+		return "", "", http.StatusBadRequest, fmt.Errorf("%s is empty", label)
+	}
+
+	format, err := utils.DetectFormat(tempFile.Name())
+	if err == nil && format == utils.FormatUnknown {
+		err = fmt.Errorf("%w: expected PDF, DOCX, RTF, HTML, Markdown or plain text", utils.ErrUnsupportedFormat)
+	}
+	if err != nil {
+		os.Remove(tempFile.Name())
+		return "", "", parseErrorStatus(err), err
+	}
+
+	return tempFile.Name(), name, http.StatusOK, nil
+}
+
+func isBodyTooLarge(err error) bool {
+	var maxErr *http.MaxBytesError
+	return errors.As(err, &maxErr)
+}
+
+// parserMode picks the resume parser from ?mode=, defaulting to RESUME_PARSER_MODE.
+func parserMode(r *http.Request) string {
+	if mode := r.URL.Query().Get("mode"); mode != "" {
+		return mode
+	}
+	return config.AppConfig.ResumeParserMode
+}
+
+// parseOptions combines the parser mode with the PII redaction setting of
+// the bearer token's tenant: the X-Tenant-ID one if the user may act for it.
+// ?to_english=true asks for the values translated into English.
+func parseOptions(r *http.Request, tenants *service.TenantService) service.ParseOptions {
+	userID, err := bearerUserID(r)
+	if err != nil {
+		userID = uuid.Nil // anonymous: the default settings
+	}
+	opts := tenants.ParseOptions(tenants.TenantFor(userID, r.Header.Get("X-Tenant-ID")), parserMode(r))
+	opts.ToEnglish, _ = strconv.ParseBool(r.URL.Query().Get("to_english"))
+	return opts
+}
+
+// parseErrorStatus maps a resume or job description parsing error to the HTTP status to reply with.
+func parseErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidParserMode):
+		return http.StatusBadRequest
+	case errors.Is(err, utils.ErrUnsupportedFormat):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, utils.ErrTooManyPages), errors.Is(err, utils.ErrDecompressionBomb):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, utils.ErrMalformedDocument), errors.Is(err, utils.ErrExtractionTimeout):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, service.ErrInvalidResumeOutput), errors.Is(err, service.ErrInvalidJobOutput):
+		return http.StatusBadGateway
+	case errors.Is(err, utils.ErrExtractionBusy):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
 }
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/satyam-svg/resume-parser/internal/service"
+	"gorm.io/gorm"
+)
+
+// ResumeRenderHandler serves profiles rendered as formatted resumes.
+type ResumeRenderHandler struct {
+	Profiles *service.ProfileService
+	Renders  *service.ResumeRenderService
+}
+
+// Templates lists the resume templates: GET /resume-templates
+func (h *ResumeRenderHandler) Templates(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"default":   service.DefaultResumeTemplate,
+		"templates": service.ResumeTemplates,
+	})
+}
+
+// Render serves the profile as a PDF or an HTML preview:
+// GET /user/{id}/resume.pdf?template=modern, GET /user/{id}/resume.html?template=modern
+func (h *ResumeRenderHandler) Render(w http.ResponseWriter, r *http.Request, userID, format string) {
+	name := r.URL.Query().Get("template")
+	if name == "" {
+		name = service.DefaultResumeTemplate
+	}
+	tmpl, err := service.FindResumeTemplate(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.Profiles.GetUser(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to load user", http.StatusInternalServerError)
+		return
+	}
+
+	render, err := h.Renders.Render(user, tmpl, format)
+	if errors.Is(err, service.ErrUnsupportedPDFText) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to render resume", http.StatusInternalServerError)
+		return
+	}
+
+	etag := `"` + render.ProfileHash[:32] + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if format == service.ResumeFormatPDF {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `inline; filename="`+resumeFileName(user.FullName)+`.pdf"`)
+	} else {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	}
+	w.Write(render.Content)
+}
+
+// resumeFileName turns "Jane Q. Doe" into "Jane-Q-Doe-Resume".
+func resumeFileName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return r > unicode.MaxASCII || !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(append(words, "Resume"), "-")
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/satyam-svg/resume-parser/internal/service"
+	"gorm.io/gorm"
+)
+
+// ResumeReportHandler serves resume quality and ATS-readiness reports.
+type ResumeReportHandler struct {
+	Profiles *service.ProfileService
+	Reports  *service.ResumeReportService
+}
+
+// Report scores the user's resume: GET /user/{id}/resume/report
+// ?resume_id= picks a stored version (default: the primary one, or the
+// profile without any), ?job_id=1&job_id=2 the jobs to compare keywords with
+// (default: the ones applied to) and ?rewrite=true asks Gemini to rewrite
+// bullets without figures. Only the owner may ask: the report reads stored
+// resumes and applications, and rewriting spends Gemini calls.
+func (h *ResumeReportHandler) Report(w http.ResponseWriter, r *http.Request, userID string) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	if !requireOwner(w, r, uid) {
+		return
+	}
+
+	q := r.URL.Query()
+	var opts service.ReportOptions
+	if v := q.Get("resume_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			http.Error(w, "Invalid resume ID", http.StatusBadRequest)
+			return
+		}
+		opts.ResumeID = &id
+	}
+	for _, v := range q["job_id"] {
+		for _, s := range strings.Split(v, ",") {
+			id, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+			if err != nil {
+				http.Error(w, "Invalid job ID: "+s, http.StatusBadRequest)
+				return
+			}
+			opts.JobIDs = append(opts.JobIDs, uint(id))
+		}
+	}
+	opts.Rewrite, _ = strconv.ParseBool(q.Get("rewrite"))
+
+	user, err := h.Profiles.GetUser(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to load user", http.StatusInternalServerError)
+		return
+	}
+
+	report, err := h.Reports.Report(user, opts)
+	switch {
+	case errors.Is(err, service.ErrResumeNotFound), errors.Is(err, service.ErrTargetJobNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		log.Printf("❌ Resume report for user %s failed: %v", user.ID, err)
+		http.Error(w, "Failed to analyze resume", parseErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/satyam-svg/resume-parser/internal/service"
+	"github.com/satyam-svg/resume-parser/internal/utils"
+	"gorm.io/gorm"
+)
+
+// ResumeVersionHandler manages the original resume documents a user has
+// uploaded, kept as numbered versions.
+type ResumeVersionHandler struct {
+	Resumes *service.ResumeService
+	Tenants *service.TenantService
+}
+
+// Upload stores a resume as the user's next version along with its parse:
+// POST /user/{id}/resumes (multipart "resume", or the document as the body).
+// It replies 201, or 200 with the existing version when the same document
+// was uploaded before. Like the other version endpoints except Download, it
+// needs "Authorization: Bearer <token>" of the user.
+func (h *ResumeVersionHandler) Upload(w http.ResponseWriter, r *http.Request, userID string) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	if !requireOwner(w, r, uid) {
+		return
+	}
+	opts := parseOptions(r, h.Tenants)
+	if err := service.ValidateParserMode(opts.Mode); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	path, name, status, err := saveUploadedResume(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	defer os.Remove(path)
+
+	resume, created, err := h.Resumes.Upload(uid, path, name, opts)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to store resume: "+err.Error(), parseErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if created {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(resume)
+}
+
+// List returns the user's resume versions with their parses, newest first:
+// GET /user/{id}/resumes
+func (h *ResumeVersionHandler) List(w http.ResponseWriter, r *http.Request, userID string) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	if !requireOwner(w, r, uid) {
+		return
+	}
+
+	resumes, err := h.Resumes.List(uid)
+	if err != nil {
+		http.Error(w, "Failed to fetch resumes", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"user_id": uid,
+		"resumes": resumes,
+	})
+}
+
+// SetPrimary makes a version the one shared by default:
+// POST /user/{id}/resumes/{resumeID}/primary
+func (h *ResumeVersionHandler) SetPrimary(w http.ResponseWriter, r *http.Request, userID, resumeID string) {
+	uid, rid, ok := parseResumeIDs(w, userID, resumeID)
+	if !ok || !requireOwner(w, r, uid) {
+		return
+	}
+
+	resume, err := h.Resumes.SetPrimary(uid, rid)
+	if errors.Is(err, service.ErrResumeNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to update resume", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resume)
+}
+
+// Download serves the original document: GET /user/{id}/resumes/{resumeID}/download
+// with "Authorization: Bearer <token>" of the owner, or of the recruiter of a
+// job the version was submitted to.
+func (h *ResumeVersionHandler) Download(w http.ResponseWriter, r *http.Request, userID, resumeID string) {
+	viewer, err := bearerUserID(r)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	uid, rid, ok := parseResumeIDs(w, userID, resumeID)
+	if !ok {
+		return
+	}
+
+	resume, err := h.Resumes.Get(uid, rid)
+	if errors.Is(err, service.ErrResumeNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to load resume", http.StatusInternalServerError)
+		return
+	}
+
+	allowed, err := h.Resumes.CanDownload(viewer, resume)
+	if err != nil {
+		http.Error(w, "Failed to load resume", http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	file, err := h.Resumes.Open(resume)
+	if err != nil {
+		log.Printf("❌ Stored file of resume %s unavailable: %v", resume.ID, err)
+		http.Error(w, "Resume file unavailable", http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", utils.FormatContentType(resume.Format))
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": resume.FileName})
+	if disposition == "" {
+		disposition = "attachment"
+	}
+	w.Header().Set("Content-Disposition", disposition)
+	w.Header().Set("Content-Length", strconv.FormatInt(resume.Size, 10))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	io.Copy(w, file)
+}
+
+func parseResumeIDs(w http.ResponseWriter, userID, resumeID string) (uuid.UUID, uuid.UUID, bool) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	rid, err := uuid.Parse(resumeID)
+	if err != nil {
+		http.Error(w, "Invalid resume ID", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return uid, rid, true
+}
+
+// bearerUserID returns the user of the request's "Authorization: Bearer" token.
+func bearerUserID(r *http.Request) (uuid.UUID, error) {
+	return utils.BearerUserID(r)
+}
+
+// requireOwner lets the request through when its bearer token belongs to
+// userID, and replies 401 or 403 otherwise.
+func requireOwner(w http.ResponseWriter, r *http.Request, userID uuid.UUID) bool {
+	viewer, err := bearerUserID(r)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	if viewer != userID {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Job engagement event types
+const (
+	EventView        = "view"        // job shown in a listing
+	EventDetailOpen  = "detail_open" // job detail page opened
+	EventApplyClick  = "apply_click" // apply button clicked
+	EventApplication = "application" // application submitted (counted from job_applications)
+)
+
+type JobEvent struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	JobID     uint       `gorm:"index" json:"job_id"`
+	Type      string     `gorm:"index" json:"type"`
+	VisitorID string     `json:"visitor_id"` // hashed IP + user agent
+	UserID    *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+	CreatedAt time.Time  `gorm:"index" json:"created_at"`
+}
+
+type JobEventCounts struct {
+	Views        int `json:"views"`
+	DetailOpens  int `json:"detail_opens"`
+	ApplyClicks  int `json:"apply_clicks"`
+	Applications int `json:"applications"`
+}
+
+type JobConversionRates struct {
+	ViewToDetail       float64 `json:"view_to_detail"`
+	DetailToApplyClick float64 `json:"detail_to_apply_click"`
+	ApplyClickToApply  float64 `json:"apply_click_to_application"`
+	ViewToApplication  float64 `json:"view_to_application"`
+}
+
+type JobAnalyticsPoint struct {
+	Date string `json:"date"` // YYYY-MM-DD (UTC)
+	JobEventCounts
+}
+
+type JobAnalytics struct {
+	JobID      uint                `json:"job_id"`
+	Title      string              `json:"title"`
+	Totals     JobEventCounts      `json:"totals"`
+	Conversion JobConversionRates  `json:"conversion"`
+	Series     []JobAnalyticsPoint `json:"series"`
+}
+
+type RecruiterAnalyticsResponse struct {
+	RecruiterID string         `json:"recruiter_id"`
+	From        string         `json:"from"`
+	To          string         `json:"to"`
+	Totals      JobEventCounts `json:"totals"`
+	Jobs        []JobAnalytics `json:"jobs"`
+}
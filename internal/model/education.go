@@ -13,6 +13,9 @@ type Education struct {
 	Degree      string    `json:"degree"`
 	GPA         string    `json:"gpa"`
 	Years       string    `json:"years"`
+	Period      DateRange `gorm:"embedded;embeddedPrefix:period_" json:"period"` // normalized Years
+
+	Extensions map[string]interface{} `gorm:"serializer:json" json:"-"` // JSON Resume fields kept for export
 }
 
 func (e *Education) BeforeCreate(tx *gorm.DB) (err error) {
@@ -12,7 +12,10 @@ type Experience struct {
 	Location    string    `json:"location"`
 	Title       string    `json:"title"`
 	Years       string    `json:"years"`
+	Period      DateRange `gorm:"embedded;embeddedPrefix:period_" json:"period"` // normalized Years
 	Description string    `json:"description"`
+
+	Extensions map[string]interface{} `gorm:"serializer:json" json:"-"` // JSON Resume fields kept for export
 }
 
 func (e *Experience) BeforeCreate(tx *gorm.DB) (err error) {
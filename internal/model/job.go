@@ -11,23 +11,43 @@ type Job struct {
 	Title       string    `json:"title"`
 	Company     string    `json:"company"`
 	Location    string    `json:"location"`
-	SalaryMin   int       `json:"salary_min"`
-	SalaryMax   int       `json:"salary_max"`
+	Geo         Location  `gorm:"embedded;embeddedPrefix:geo_" json:"geo"` // normalized Location
+	SalaryMin   float64   `json:"salary_min"`
+	SalaryMax   float64   `json:"salary_max"`
 	Type        string    `json:"type"` // Full-time, Part-time, etc.
 	Description string    `json:"description"`
 	Tags        string    `json:"tags"`
+	Status      string    `gorm:"default:published;index" json:"status"` // draft or published; only published jobs are listed
 	CreatedAt   time.Time `json:"created_at"`
 
+	SalaryCurrency   string            `gorm:"default:USD" json:"salary_currency"` // USD, EUR, INR, USDC, ETH...
+	SalaryPeriod     string            `gorm:"default:year" json:"salary_period"`  // hour, day, week, month, year
+	TokenChain       string            `json:"token_chain,omitempty"`              // e.g. ethereum, polygon (token pay only)
+	TokenContract    string            `json:"token_contract,omitempty"`           // ERC-20 contract address (token pay only)
+	SalaryNormalized *NormalizedSalary `gorm:"-" json:"salary_normalized,omitempty"`
+
 	RecruiterID uuid.UUID `json:"recruiter_id"`                    // New field
 	Recruiter   User      `gorm:"foreignKey:RecruiterID" json:"-"` // Avoid recursive json
 }
 
+// Job statuses
+const (
+	JobStatusDraft     = "draft" // parsed from a job description, waiting for the recruiter to review it
+	JobStatusPublished = "published"
+)
+
 type JobApplication struct {
 	ID        uint      `gorm:"primaryKey"`
-	JobID     uint      `json:"job_id"`
-	UserID    uuid.UUID `json:"user_id"` // applicant
+	JobID     uint      `gorm:"uniqueIndex:idx_application_job_user" json:"job_id"`
+	UserID    uuid.UUID `gorm:"uniqueIndex:idx_application_job_user" json:"user_id"` // applicant
 	CreatedAt time.Time
 
+	Stage      string              `gorm:"default:applied" json:"stage"` // applied, screening, interview, offer, hired, rejected
+	ReferralID *uint               `json:"referral_id,omitempty"`
+	ResumeID   *uuid.UUID          `gorm:"type:uuid;index" json:"resume_id,omitempty"`
+	KnockedOut bool                `json:"knocked_out"` // failed a screening knockout rule
+	Answers    []ApplicationAnswer `gorm:"foreignKey:ApplicationID" json:"answers"`
+
 	Job  Job  `gorm:"foreignKey:JobID"`
 	User User `gorm:"foreignKey:UserID"`
 }
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Work modes
+const (
+	WorkModeOnsite = "onsite"
+	WorkModeHybrid = "hybrid"
+	WorkModeRemote = "remote"
+)
+
+// Location precisions, from most to least specific
+const (
+	PrecisionCity    = "city"
+	PrecisionRegion  = "region"
+	PrecisionCountry = "country"
+)
+
+// Location is a normalized place resolved against the bundled gazetteer.
+// It is embedded into Job and User next to their free-text Location.
+type Location struct {
+	City      string  `json:"city"`
+	Region    string  `json:"region"`
+	Country   string  `json:"country"` // ISO 3166-1 alpha-2
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Precision string  `json:"precision"` // city, region, country or empty when unresolved
+	WorkMode  string  `json:"work_mode"` // onsite, hybrid, remote
+}
+
+// HasCoordinates reports whether the location is precise enough for distance search.
+func (l Location) HasCoordinates() bool {
+	return l.Precision == PrecisionCity
+}
+
+// String renders the location as "City, Region, CC (work mode)".
+func (l Location) String() string {
+	var parts []string
+	for _, p := range []string{l.City, l.Region, l.Country} {
+		if p != "" && (len(parts) == 0 || parts[len(parts)-1] != p) {
+			parts = append(parts, p)
+		}
+	}
+
+	place := strings.Join(parts, ", ")
+	switch {
+	case place == "" && l.WorkMode == WorkModeRemote:
+		return "Remote"
+	case place == "":
+		return ""
+	case l.WorkMode != "":
+		return fmt.Sprintf("%s (%s)", place, l.WorkMode)
+	}
+	return place
+}
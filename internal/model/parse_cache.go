@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// ParseCacheEntry is a cached Gemini parse, keyed by a content hash.
+type ParseCacheEntry struct {
+	Key         string       `gorm:"primaryKey;column:cache_key"` // "file:<sha256>" of the upload or "text:<sha256>" of its extracted text
+	Fingerprint string       // prompt, schema and model the result was produced with
+	Result      *ParseResult `gorm:"serializer:json"`
+	CreatedAt   time.Time
+	ExpiresAt   time.Time `gorm:"index"`
+}
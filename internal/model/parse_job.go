@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Parse job statuses
+const (
+	ParseJobQueued    = "queued"
+	ParseJobRunning   = "running"
+	ParseJobSucceeded = "succeeded"
+	ParseJobFailed    = "failed"
+)
+
+// ParseJob is an asynchronous resume parse queued by /upload.
+type ParseJob struct {
+	ID          uuid.UUID    `gorm:"type:uuid;primaryKey" json:"id"`
+	Status      string       `gorm:"index" json:"status"`
+	Mode        string       `json:"mode"`
+	RedactPII   bool         `json:"redact_pii"`
+	ToEnglish   bool         `json:"to_english"`
+	Attempts    int          `json:"attempts"`
+	Error       string       `json:"error,omitempty"`
+	Result      *ParseResult `gorm:"serializer:json" json:"result,omitempty"`
+	FilePath    string       `json:"-"` // uploaded document, removed once the job finishes
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	CompletedAt *time.Time   `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time   `gorm:"index" json:"expires_at,omitempty"` // finished jobs are deleted after this
+}
+
+// Done reports whether the job has finished, successfully or not.
+func (j *ParseJob) Done() bool {
+	return j.Status == ParseJobSucceeded || j.Status == ParseJobFailed
+}
@@ -0,0 +1,28 @@
+package model
+
+// ParsedJob is the structured output of job description parsing.
+type ParsedJob struct {
+	Title          string     `json:"title"`
+	Company        string     `json:"company"`
+	Location       string     `json:"location"`
+	SalaryMin      float64    `json:"salary_min"`
+	SalaryMax      float64    `json:"salary_max"`
+	SalaryCurrency string     `json:"salary_currency"` // ISO 4217 or token code, e.g. USD, INR, USDC
+	SalaryPeriod   string     `json:"salary_period"`   // hour, day, week, month or year
+	Type           string     `json:"type"`            // Full-time, Part-time, Contract, Internship or Temporary
+	Tags           StringList `json:"tags"`
+	Description    string     `json:"description"`
+}
+
+// JobParseResult is the response of job description parsing: the draft job
+// it was saved as, and how it was parsed.
+type JobParseResult struct {
+	Format         string   `json:"format"`                    // detected document format
+	Parser         string   `json:"parser"`                    // gemini or heuristic
+	FallbackReason string   `json:"fallback_reason,omitempty"` // why Gemini was not used in auto mode
+	Repairs        int      `json:"repairs"`                   // re-asks needed to get schema-valid output
+	Warnings       []string `json:"warnings,omitempty"`        // what could not be kept, e.g. an unknown salary currency
+	Job            *Job     `json:"job"`
+
+	Parsed *ParsedJob `json:"-"`
+}
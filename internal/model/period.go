@@ -0,0 +1,73 @@
+package model
+
+import "time"
+
+// Date range precisions
+const (
+	PeriodPrecisionMonth    = "month"
+	PeriodPrecisionYear     = "year"
+	PeriodPrecisionDuration = "duration" // only the length is known, e.g. "3 yrs"
+)
+
+// DateRange is the normalized form of a free-text period such as
+// "Jan 2021 – Present". Dates are the first day of their month, or of their
+// year at year precision.
+type DateRange struct {
+	Start          *time.Time `json:"start"`
+	End            *time.Time `json:"end"`                       // nil while ongoing
+	Precision      string     `json:"precision"`                 // month, year, duration or empty when unparsed
+	Ongoing        bool       `json:"ongoing"`                   // runs until today ("Present")
+	DurationMonths int        `json:"duration_months,omitempty"` // length when only that is known
+}
+
+// Span returns the period as [from, to), counting an ongoing one up to now.
+// "Jan 2021 – Mar 2021" covers three months; "2019 – 2021" two years and a
+// lone "2019" one year. ok is false without a start date.
+func (d DateRange) Span(now time.Time) (from, to time.Time, ok bool) {
+	if d.Start == nil {
+		return time.Time{}, time.Time{}, false
+	}
+	from = *d.Start
+	switch {
+	case d.Ongoing || d.End == nil:
+		to = now
+	case d.Precision == PeriodPrecisionMonth:
+		to = d.End.AddDate(0, 1, 0)
+	case d.End.Equal(from):
+		to = from.AddDate(1, 0, 0)
+	default:
+		to = *d.End
+	}
+	if to.Before(from) {
+		to = from
+	}
+	return from, to, true
+}
+
+// Months is the length of the period in whole months.
+func (d DateRange) Months(now time.Time) int {
+	if d.Precision == PeriodPrecisionDuration {
+		return d.DurationMonths
+	}
+	from, to, ok := d.Span(now)
+	if !ok {
+		return 0
+	}
+	return MonthsBetween(from, to)
+}
+
+// MonthsBetween counts the whole months from one date to a later one.
+func MonthsBetween(from, to time.Time) int {
+	m := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+	if to.Day() < from.Day() {
+		m--
+	}
+	return max(m, 0)
+}
+
+// ExperienceSummary adds up a user's work experience.
+type ExperienceSummary struct {
+	TotalYears       float64 `json:"total_years"`       // all positions added up
+	OverlappingYears float64 `json:"overlapping_years"` // time counted in more than one position at once
+	NetYears         float64 `json:"net_years"`         // total without the overlap
+}
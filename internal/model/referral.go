@@ -0,0 +1,80 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Application pipeline stages, in order. Rejected is terminal and never
+// counts as reaching a later stage.
+const (
+	StageApplied   = "applied"
+	StageScreening = "screening"
+	StageInterview = "interview"
+	StageOffer     = "offer"
+	StageHired     = "hired"
+	StageRejected  = "rejected"
+)
+
+var PipelineStages = []string{StageApplied, StageScreening, StageInterview, StageOffer, StageHired}
+
+// StageRank returns the position of a stage in the pipeline, or -1 for
+// rejected and unknown stages.
+func StageRank(stage string) int {
+	for i, s := range PipelineStages {
+		if s == stage {
+			return i
+		}
+	}
+	return -1
+}
+
+// Referral is a per-user, per-job referral code shared by community members.
+type Referral struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Code       string    `gorm:"uniqueIndex" json:"code"`
+	ReferrerID uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_referral_referrer_job" json:"referrer_id"`
+	JobID      uint      `gorm:"index;uniqueIndex:idx_referral_referrer_job" json:"job_id"` // one code per referrer and job
+	CreatedAt  time.Time `json:"created_at"`
+
+	Job Job `gorm:"foreignKey:JobID" json:"-"`
+}
+
+type ReferralClick struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ReferralID uint      `gorm:"index" json:"referral_id"`
+	VisitorID  string    `json:"visitor_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ReferralReward records credits granted for a referred application, at most once per application.
+type ReferralReward struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ReferralID    uint      `gorm:"index" json:"referral_id"`
+	ApplicationID uint      `gorm:"uniqueIndex" json:"application_id"`
+	ReferrerID    uuid.UUID `gorm:"type:uuid;index" json:"referrer_id"`
+	Stage         string    `json:"stage"`
+	Credits       int       `json:"credits"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type ReferralStats struct {
+	Code           string `json:"code"`
+	JobID          uint   `json:"job_id"`
+	JobTitle       string `json:"job_title"`
+	Clicks         int64  `json:"clicks"`
+	UniqueVisitors int64  `json:"unique_visitors"`
+	Applications   int64  `json:"applications"`
+	Rewarded       int64  `json:"rewarded"`
+	CreditsEarned  int64  `json:"credits_earned"`
+}
+
+type ReferralDashboard struct {
+	UserID        uuid.UUID       `json:"user_id"`
+	Clicks        int64           `json:"clicks"`
+	Applications  int64           `json:"applications"`
+	Rewarded      int64           `json:"rewarded"`
+	CreditsEarned int64           `json:"credits_earned"`
+	Referrals     []ReferralStats `json:"referrals"`
+}
@@ -0,0 +1,176 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ResumeSchemaVersion is the version of the ParsedResume contract returned
+// by /upload. It changes whenever a field is renamed, removed or retyped.
+const ResumeSchemaVersion = "1"
+
+// ParseResult is the response envelope of resume parsing.
+type ParseResult struct {
+	SchemaVersion  string        `json:"schema_version"`
+	Format         string        `json:"format"`                    // detected document format
+	Parser         string        `json:"parser"`                    // gemini or heuristic
+	FallbackReason string        `json:"fallback_reason,omitempty"` // why Gemini was not used in auto mode
+	Repairs        int           `json:"repairs"`                   // re-asks needed to get schema-valid output
+	Warnings       []string      `json:"warnings,omitempty"`        // schema problems the heuristic parser could not avoid
+	Cached         bool          `json:"cached,omitempty"`          // served from the parse cache
+	Redactions     int           `json:"redactions,omitempty"`      // PII values withheld from Gemini and restored locally
+	Resume         *ParsedResume `json:"resume"`
+
+	// Language is the ISO 639-1 code of the language the resume is written
+	// in, as detected from its text, and LanguageConfidence how sure the
+	// detection is (0-1). Empty when the text is too short to tell.
+	Language           string  `json:"language,omitempty"`
+	LanguageConfidence float64 `json:"language_confidence,omitempty"`
+	TranslatedTo       string  `json:"translated_to,omitempty"` // set when values were normalized into another language
+
+	// Fields scores every non-empty value of Resume, keyed by its path
+	// ("email", "skills[2]", "experience[0].title"), and says where in the
+	// document it was found.
+	Fields        map[string]FieldConfidence `json:"fields,omitempty"`
+	LowConfidence []string                   `json:"low_confidence,omitempty"` // paths of Fields below LowFieldConfidence
+}
+
+// LowFieldConfidence is the confidence below which a parsed value should be
+// checked by hand
+const LowFieldConfidence = 0.5
+
+// FieldConfidence is how far a parsed value can be trusted, from 0 to 1,
+// and where it came from.
+type FieldConfidence struct {
+	Confidence float64      `json:"confidence"`
+	Source     *FieldSource `json:"source,omitempty"` // nil when the value is not in the document as written
+}
+
+// FieldSource is where a parsed value was found in the extracted text.
+type FieldSource struct {
+	Page  int    `json:"page,omitempty"` // from 1; PDFs only
+	Start int    `json:"start"`          // byte offsets into the extracted text
+	End   int    `json:"end"`
+	Text  string `json:"text"` // the snippet as it appears in the document
+	BBox  *BBox  `json:"bbox,omitempty"`
+}
+
+// BBox is a box on a PDF page, in points from its bottom left corner.
+type BBox struct {
+	X0 float64 `json:"x0"`
+	Y0 float64 `json:"y0"`
+	X1 float64 `json:"x1"`
+	Y1 float64 `json:"y1"`
+}
+
+// ParsedResume is the structured output of resume parsing.
+type ParsedResume struct {
+	FullName       string             `json:"full_name"`
+	Title          string             `json:"title"`
+	Location       string             `json:"location"`
+	Email          string             `json:"email"`
+	Phone          string             `json:"phone"`
+	CurrentCompany string             `json:"current_company"`
+	LinkedIn       string             `json:"linkedin"`
+	GitHub         string             `json:"github"`
+	Portfolio      string             `json:"portfolio"`
+	Skills         StringList         `json:"skills"`
+	Experience     []ParsedExperience `json:"experience"`
+	Education      []ParsedEducation  `json:"education"`
+}
+
+type ParsedExperience struct {
+	Company     string     `json:"company"`
+	Location    string     `json:"location"`
+	Title       string     `json:"title"`
+	Years       string     `json:"years"`
+	Period      *DateRange `json:"period,omitempty"` // normalized Years, filled in after parsing
+	Description string     `json:"description"`
+}
+
+type ParsedEducation struct {
+	Institution string     `json:"institution"`
+	Location    string     `json:"location"`
+	Degree      string     `json:"degree"`
+	GPA         string     `json:"gpa"`
+	Years       string     `json:"years"`
+	Period      *DateRange `json:"period,omitempty"` // normalized Years, filled in after parsing
+}
+
+// StringList accepts either a JSON array of strings or a single
+// comma-separated string ("Go, Solidity").
+type StringList []string
+
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*l = compactStrings(list)
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*l = compactStrings(strings.Split(s, ","))
+	return nil
+}
+
+func compactStrings(in []string) []string {
+	out := make([]string, 0, len(in))
+	for _, s := range in {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Merge strategies for applying a parsed resume to a profile section
+const (
+	MergeStrategyMerge   = "merge"   // keep current values, add what is missing
+	MergeStrategyReplace = "replace" // overwrite with parsed values
+	MergeStrategySkip    = "skip"    // leave the section untouched
+)
+
+// MergeChoices picks a strategy per profile section.
+type MergeChoices struct {
+	Basics     string `json:"basics"`
+	Skills     string `json:"skills"`
+	Education  string `json:"education"`
+	Experience string `json:"experience"`
+}
+
+type FieldDiff struct {
+	Field   string `json:"field"`
+	Current string `json:"current"`
+	Parsed  string `json:"parsed"`
+	Changed bool   `json:"changed"`
+}
+
+type SkillsDiff struct {
+	Current []string `json:"current"`
+	Parsed  []string `json:"parsed"`
+	Added   []string `json:"added"`   // in the resume but not on the profile
+	Missing []string `json:"missing"` // on the profile but not in the resume
+}
+
+type EducationDiff struct {
+	Current []Education       `json:"current"`
+	Parsed  []ParsedEducation `json:"parsed"`
+	New     []ParsedEducation `json:"new"` // parsed entries not on the profile yet
+}
+
+type ExperienceDiff struct {
+	Current []Experience       `json:"current"`
+	Parsed  []ParsedExperience `json:"parsed"`
+	New     []ParsedExperience `json:"new"`
+}
+
+// MergePreview is a field-by-field diff between a profile and a parsed resume.
+type MergePreview struct {
+	Basics     []FieldDiff    `json:"basics"`
+	Skills     SkillsDiff     `json:"skills"`
+	Education  EducationDiff  `json:"education"`
+	Experience ExperienceDiff `json:"experience"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Resume is one stored version of a user's original resume document.
+type Resume struct {
+	ID         uuid.UUID    `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID     uuid.UUID    `gorm:"type:uuid;uniqueIndex:idx_resume_version" json:"user_id"`
+	Version    int          `gorm:"uniqueIndex:idx_resume_version" json:"version"` // 1 for the first upload, then counting up
+	FileName   string       `json:"file_name"`
+	Format     string       `json:"format"` // detected document format
+	SHA256     string       `gorm:"index" json:"sha256"`
+	Size       int64        `json:"size"`
+	IsPrimary  bool         `json:"is_primary"` // the version shared by default, e.g. when applying
+	StorageKey string       `json:"-"`
+	Parsed     *ParseResult `gorm:"serializer:json" json:"parsed,omitempty"` // parse of this version when it was uploaded
+	CreatedAt  time.Time    `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ResumeRender is a profile rendered as a formatted resume, kept until the
+// profile or the template changes.
+type ResumeRender struct {
+	UserID      uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Template    string    `gorm:"primaryKey"`
+	Format      string    `gorm:"primaryKey"` // "pdf" or "html"
+	ProfileHash string    // of what was rendered; a different hash means the cached copy is stale
+	Content     []byte
+	CreatedAt   time.Time
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Report check names
+const (
+	ReportCheckSections = "sections"
+	ReportCheckContact  = "contact"
+	ReportCheckBullets  = "quantified_bullets"
+	ReportCheckDates    = "date_gaps"
+	ReportCheckKeywords = "keywords"
+	ReportCheckLength   = "length"
+)
+
+// Report check statuses
+const (
+	ReportStatusPass    = "pass"
+	ReportStatusWarn    = "warn"
+	ReportStatusFail    = "fail"
+	ReportStatusSkipped = "skipped" // nothing to check, e.g. no target jobs
+)
+
+// Where a report's resume came from
+const (
+	ReportSourceResume  = "resume"  // a stored resume version
+	ReportSourceProfile = "profile" // the profile, for users without one
+)
+
+// ResumeReport scores how well a resume reads to applicant tracking systems
+// and recruiters, with what to fix first.
+type ResumeReport struct {
+	UserID      uuid.UUID     `json:"user_id"`
+	Source      string        `json:"source"`              // resume or profile
+	ResumeID    *uuid.UUID    `json:"resume_id,omitempty"` // the version analyzed, when Source is resume
+	Score       int           `json:"score"`               // 0-100, the weighted checks that ran
+	Checks      []ReportCheck `json:"checks"`
+	Suggestions []string      `json:"suggestions"` // of all checks, the lowest scoring first
+
+	TargetJobs          []uint          `json:"target_jobs"` // the jobs keywords were compared with
+	Keywords            []KeywordMatch  `json:"keywords"`
+	UnquantifiedBullets []ResumeBullet  `json:"unquantified_bullets"`
+	Gaps                []EmploymentGap `json:"gaps"`
+	WordCount           int             `json:"word_count"`
+	PageCount           int             `json:"page_count,omitempty"` // PDFs only
+
+	// Rewrites of unquantified bullets, when asked for. RewriteError says
+	// why there are none; the checks above do not depend on them.
+	Rewrites     []BulletRewrite `json:"rewrites,omitempty"`
+	RewriteError string          `json:"rewrite_error,omitempty"`
+
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// ReportCheck is the outcome of one deterministic check.
+type ReportCheck struct {
+	Name        string   `json:"name"`
+	Status      string   `json:"status"`
+	Score       int      `json:"score"` // 0-100
+	Message     string   `json:"message"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// KeywordMatch is how a resume covers one skill the target jobs ask for.
+type KeywordMatch struct {
+	Keyword  string  `json:"keyword"`
+	Jobs     int     `json:"jobs"`     // target jobs tagged with it
+	Mentions int     `json:"mentions"` // in the resume text, aliases included
+	Density  float64 `json:"density"`  // mentions per 100 words
+}
+
+// ResumeBullet is one line of an experience description.
+type ResumeBullet struct {
+	Path     string `json:"path"`     // e.g. "experience[0]"
+	Position string `json:"position"` // e.g. "Backend Engineer at Acme"
+	Text     string `json:"text"`
+}
+
+// EmploymentGap is time between two positions that no position covers.
+type EmploymentGap struct {
+	After  string    `json:"after"`  // the position that ended
+	Before string    `json:"before"` // the position that started
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	Months int       `json:"months"`
+}
+
+// BulletRewrite is a suggested stronger wording of a bullet.
+type BulletRewrite struct {
+	Path     string `json:"path"`
+	Original string `json:"original"`
+	Rewrite  string `json:"rewrite"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Pay periods
+const (
+	PayPeriodHour  = "hour"
+	PayPeriodDay   = "day"
+	PayPeriodWeek  = "week"
+	PayPeriodMonth = "month"
+	PayPeriodYear  = "year"
+)
+
+// PayPeriodsPerYear converts a pay period to its annual multiplier
+// (40h weeks, 260 working days).
+var PayPeriodsPerYear = map[string]float64{
+	PayPeriodHour:  2080,
+	PayPeriodDay:   260,
+	PayPeriodWeek:  52,
+	PayPeriodMonth: 12,
+	PayPeriodYear:  1,
+}
+
+// ExchangeRate is a locally maintained conversion rate for a fiat currency
+// or token, expressed as the USD value of one unit.
+type ExchangeRate struct {
+	Currency   string    `gorm:"primaryKey" json:"currency"` // ISO 4217 code or token symbol (USDC, ETH)
+	USDPerUnit float64   `json:"usd_per_unit"`
+	IsCrypto   bool      `json:"is_crypto"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// NormalizedSalary is a job's salary range converted to an annual amount
+// in the requested currency.
+type NormalizedSalary struct {
+	Currency string  `json:"currency"`
+	Period   string  `json:"period"`
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+}
@@ -0,0 +1,36 @@
+package model
+
+// Screening question types
+const (
+	QuestionYesNo          = "yes_no"
+	QuestionMultipleChoice = "multiple_choice"
+	QuestionNumeric        = "numeric"
+	QuestionText           = "text"
+)
+
+// ScreeningQuestion is asked to applicants when they apply to a job.
+// An answer listed in KnockoutAnswers, or a numeric answer outside
+// [MinValue, MaxValue], knocks the application out.
+type ScreeningQuestion struct {
+	ID       uint     `gorm:"primaryKey" json:"id"`
+	JobID    uint     `gorm:"index" json:"job_id"`
+	Position int      `json:"position"`
+	Prompt   string   `json:"prompt"` // "Do you have Solidity experience?"
+	Type     string   `json:"type"`   // yes_no, multiple_choice, numeric, text
+	Options  []string `gorm:"serializer:json" json:"options,omitempty"`
+	Required bool     `json:"required"`
+
+	KnockoutAnswers []string `gorm:"serializer:json" json:"knockout_answers,omitempty"`
+	MinValue        *float64 `json:"min_value,omitempty"`
+	MaxValue        *float64 `json:"max_value,omitempty"`
+}
+
+type ApplicationAnswer struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	ApplicationID uint   `gorm:"index" json:"application_id"`
+	QuestionID    uint   `gorm:"index" json:"question_id"`
+	Value         string `json:"value"`
+	KnockedOut    bool   `json:"knocked_out"`
+
+	Question ScreeningQuestion `gorm:"foreignKey:QuestionID" json:"-"`
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// Skill is a canonical skill of the taxonomy that profile skills and job tags
+// are normalized against.
+type Skill struct {
+	Name      string     `gorm:"primaryKey" json:"name"`         // canonical spelling, e.g. "JavaScript"
+	Category  string     `gorm:"index" json:"category"`          // language, framework, database, cloud...
+	Parent    string     `gorm:"index" json:"parent,omitempty"`  // broader skill, e.g. "JavaScript" for "React"
+	Aliases   StringList `gorm:"serializer:json" json:"aliases"` // other spellings, e.g. "js", "ecmascript"
+	Children  []string   `gorm:"-" json:"children,omitempty"`    // narrower skills, filled in on lookup
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// UnknownSkill is a skill or tag that matched nothing in the taxonomy, kept
+// so it can be added as a skill or an alias.
+type UnknownSkill struct {
+	Key       string    `gorm:"primaryKey;column:skill_key" json:"-"` // folded name
+	Name      string    `json:"name"`                                 // spelling first seen
+	Count     int       `json:"count"`                                // times seen
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
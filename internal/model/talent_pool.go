@@ -0,0 +1,66 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TalentPoolEntry links a candidate to a recruiter's talent pool.
+type TalentPoolEntry struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	RecruiterID uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_pool_candidate" json:"recruiter_id"`
+	UserID      uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_pool_candidate" json:"user_id"`
+	Source      string    `json:"source"` // how the candidate was added, e.g. bulk_import
+	ImportID    *uint     `gorm:"index" json:"import_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"candidate"`
+}
+
+const TalentSourceBulkImport = "bulk_import"
+
+// Bulk import statuses
+const (
+	BulkImportRunning   = "running"
+	BulkImportCompleted = "completed"
+	BulkImportFailed    = "failed" // interrupted before every file was processed
+)
+
+// Bulk import item statuses
+const (
+	ImportItemPending = "pending"
+	ImportItemCreated = "created" // new draft profile
+	ImportItemLinked  = "linked"  // candidate already had a profile
+	ImportItemFailed  = "failed"
+)
+
+// BulkImport is a ZIP of resumes uploaded by a recruiter, with its progress.
+type BulkImport struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	RecruiterID uuid.UUID  `gorm:"type:uuid;index" json:"recruiter_id"`
+	FileName    string     `json:"file_name"`
+	Status      string     `json:"status"`
+	RedactPII   bool       `json:"redact_pii"`
+	Total       int        `json:"total"`
+	Processed   int        `json:"processed"`
+	Created     int        `json:"created"`
+	Linked      int        `json:"linked"`
+	Failed      int        `json:"failed"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	Items []BulkImportItem `gorm:"foreignKey:ImportID" json:"items,omitempty"`
+}
+
+// BulkImportItem is one file of a bulk import.
+type BulkImportItem struct {
+	ID       uint       `gorm:"primaryKey" json:"id"`
+	ImportID uint       `gorm:"index" json:"import_id"`
+	FileName string     `json:"file_name"`
+	Status   string     `json:"status"`
+	UserID   *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+	Parser   string     `json:"parser,omitempty"` // gemini or heuristic
+	Error    string     `json:"error,omitempty"`
+}
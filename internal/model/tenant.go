@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// TenantSettings are per-customer privacy settings. A tenant's ID is its
+// owner's user ID; admins may name any tenant with the X-Tenant-ID header.
+type TenantSettings struct {
+	TenantID  string    `gorm:"primaryKey" json:"tenant_id"`
+	RedactPII bool      `json:"redact_pii"` // withhold PII from the LLM when parsing resumes
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -10,6 +12,7 @@ type User struct {
 	FullName       string    `json:"full_name"`
 	Title          string    `json:"title"`
 	Location       string    `json:"location"`
+	Geo            Location  `gorm:"embedded;embeddedPrefix:geo_" json:"geo"` // normalized Location
 	Email          string    `gorm:"unique" json:"email"`
 	Password       string    `json:"-"` // Hashed password
 	Phone          string    `json:"phone"`
@@ -20,12 +23,27 @@ type User struct {
 	Skills         string    `json:"skills"`
 	Image          string    `json:"image"`
 	Role           string    `json:"role"`
+	Status         string    `gorm:"default:active;index" json:"status"`
 	Credits        int       `json:"credits" gorm:"default:5"` // 👈 New field
+	Language       string    `json:"language"`                 // ISO 639-1 code of the language of the applied resume
+
+	// Draft profiles are claimed with a token mailed to their address; only its hash is kept
+	ClaimTokenHash string     `json:"-"`
+	ClaimExpiresAt *time.Time `json:"-"`
+
+	// JSON Resume fields with no profile field, kept for export
+	ResumeExtensions map[string]interface{} `gorm:"serializer:json" json:"-"`
 
 	Education  []Education  `json:"education" gorm:"foreignKey:UserID"`
 	Experience []Experience `json:"experience" gorm:"foreignKey:UserID"`
 }
 
+// User statuses
+const (
+	UserStatusActive = "active"
+	UserStatusDraft  = "draft" // created from an imported resume; claimed when the candidate signs up with its claim token
+)
+
 type RecruiterResponse struct {
 	ID              uuid.UUID `json:"id"`
 	FullName        string    `json:"full_name"`
@@ -39,22 +57,25 @@ type RecruiterResponse struct {
 
 // ApplicantResponse struct for applicants/admin
 type ApplicantResponse struct {
-	ID                uuid.UUID    `json:"id"`
-	FullName          string       `json:"full_name"`
-	Title             string       `json:"title"`
-	Location          string       `json:"location"`
-	Email             string       `json:"email"`
-	Phone             string       `json:"phone"`
-	CurrentCompany    string       `json:"current_company"`
-	LinkedIn          string       `json:"linkedin"`
-	GitHub            string       `json:"github"`
-	Portfolio         string       `json:"portfolio"`
-	Skills            string       `json:"skills"`
-	Image             string       `json:"image"`
-	Role              string       `json:"role"`
-	Education         []Education  `json:"education"`
-	Experience        []Experience `json:"experience"`
-	ApplicationsCount int          `json:"applications_count"` // new
+	ID                uuid.UUID         `json:"id"`
+	FullName          string            `json:"full_name"`
+	Title             string            `json:"title"`
+	Location          string            `json:"location"`
+	Geo               Location          `json:"geo"`
+	Email             string            `json:"email"`
+	Phone             string            `json:"phone"`
+	CurrentCompany    string            `json:"current_company"`
+	LinkedIn          string            `json:"linkedin"`
+	GitHub            string            `json:"github"`
+	Portfolio         string            `json:"portfolio"`
+	Skills            string            `json:"skills"`
+	Image             string            `json:"image"`
+	Role              string            `json:"role"`
+	Language          string            `json:"language"`
+	Education         []Education       `json:"education"`
+	Experience        []Experience      `json:"experience"`
+	ExperienceSummary ExperienceSummary `json:"experience_summary"`
+	ApplicationsCount int               `json:"applications_count"` // new
 }
 
 // Automatically generate UUID before creating
@@ -1,13 +1,18 @@
 package routes
 
 import (
+	"log"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
+	"github.com/satyam-svg/resume-parser/config"
 	"github.com/satyam-svg/resume-parser/internal/controller"
 	"github.com/satyam-svg/resume-parser/internal/handler"
 	"github.com/satyam-svg/resume-parser/internal/middleware"
 	"github.com/satyam-svg/resume-parser/internal/service"
+	"github.com/satyam-svg/resume-parser/internal/utils"
 	"gorm.io/gorm"
 )
 
@@ -15,16 +20,128 @@ func RegisterRoutes(db *gorm.DB) http.Handler {
 	mux := http.NewServeMux()
 
 	// Resume Parsing APIs
-	mux.HandleFunc("/upload", handler.UploadResumeHandler)
+	utils.GeminiClient.Timeout = time.Duration(config.AppConfig.GeminiTimeoutSeconds) * time.Second
+	utils.Limits.MaxPages = config.AppConfig.ResumeMaxPages
+	utils.Limits.Timeout = time.Duration(config.AppConfig.ExtractTimeoutSeconds) * time.Second
+	utils.Limits.MaxConcurrent = config.AppConfig.ExtractMaxConcurrent
+	proxies, err := utils.ParseTrustedProxies(config.AppConfig.TrustedProxies)
+	if err != nil {
+		log.Fatalf("❌ Invalid TRUSTED_PROXIES: %v", err)
+	}
+	utils.TrustedProxies = proxies
+	if ttl := config.AppConfig.ParseCacheTTLHours; ttl > 0 {
+		parseCache := service.NewParseCache(db, time.Duration(ttl)*time.Hour)
+		onShutdown(parseCache.Close)
+		service.UseParseCache(parseCache)
+	}
+	parseJobService := service.NewParseJobService(db, config.AppConfig.ParseWorkers, config.AppConfig.ParseMaxAttempts,
+		time.Duration(config.AppConfig.ParseJobRetentionHours)*time.Hour)
+	onShutdown(parseJobService.Close)
+	tenantService := &service.TenantService{DB: db, DefaultRedactPII: config.AppConfig.PIIRedaction}
+	uploadHandler := &handler.ResumeUploadHandler{Jobs: parseJobService, Tenants: tenantService}
+
+	mux.HandleFunc("/upload", uploadHandler.Upload)
 	mux.HandleFunc("/upload/profile-image", method("POST", handler.UploadProfileImageHandler))
+	mux.HandleFunc("/upload/schema", method("GET", handler.ResumeSchemaHandler))
+
+	// /upload/jobs/{id} - GET status (?wait=N to long-poll) | /upload/jobs/{id}/events - GET SSE
+	mux.HandleFunc("/upload/jobs/", method("GET", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/upload/jobs/")
+		if strings.HasSuffix(path, "/events") {
+			uploadHandler.Events(w, r, strings.TrimSuffix(path, "/events"))
+			return
+		}
+		uploadHandler.Status(w, r, path)
+	}))
 	mux.HandleFunc("/signup", method("POST", controller.Signup))
 	mux.HandleFunc("/login", method("POST", controller.Login))
 	mux.HandleFunc("/reset-password", method("POST", controller.ResetPassword))
-	mux.HandleFunc("/user/", method("GET", controller.GetUserByID))
+
+	profileService := &service.ProfileService{DB: db}
+	if err := profileService.BackfillPeriods(); err != nil {
+		log.Printf("⚠️ Period backfill failed: %v", err)
+	}
+
+	// /user/{id} - GET profile | /user/{id}/resume/preview, /user/{id}/resume/apply - POST
+	// /user/{id}/resumes - GET versions, POST upload | /user/{id}/resumes/{resumeID}/primary - POST
+	// /user/{id}/resumes/{resumeID}/download - GET | /user/{id}/resume.json - GET export, PUT import
+	// /user/{id}/resume.pdf, /user/{id}/resume.html - GET ?template= | /user/{id}/resume/report - GET
+	resumeApplyHandler := &handler.ResumeApplyHandler{Profiles: profileService, Tenants: tenantService}
+	resumeService := &service.ResumeService{DB: db, Files: &service.LocalFileStore{Dir: config.AppConfig.ResumeStorageDir}}
+	resumeVersionHandler := &handler.ResumeVersionHandler{Resumes: resumeService, Tenants: tenantService}
+	resumeReportHandler := &handler.ResumeReportHandler{
+		Profiles: profileService,
+		Reports:  &service.ResumeReportService{DB: db, Resumes: resumeService},
+	}
+	jsonResumeHandler := &handler.JSONResumeHandler{Profiles: profileService}
+	resumeRenderHandler := &handler.ResumeRenderHandler{Profiles: profileService, Renders: &service.ResumeRenderService{DB: db}}
+	mux.HandleFunc("/resume-templates", method("GET", resumeRenderHandler.Templates))
+	mux.HandleFunc("/user/", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/user/")
+
+		switch {
+		case strings.HasSuffix(path, "/resume.json") && r.Method == http.MethodGet:
+			jsonResumeHandler.Export(w, r, strings.TrimSuffix(path, "/resume.json"))
+		case strings.HasSuffix(path, "/resume.json") && r.Method == http.MethodPut:
+			jsonResumeHandler.Import(w, r, strings.TrimSuffix(path, "/resume.json"))
+		case strings.HasSuffix(path, "/resume.pdf") && r.Method == http.MethodGet:
+			resumeRenderHandler.Render(w, r, strings.TrimSuffix(path, "/resume.pdf"), service.ResumeFormatPDF)
+		case strings.HasSuffix(path, "/resume.html") && r.Method == http.MethodGet:
+			resumeRenderHandler.Render(w, r, strings.TrimSuffix(path, "/resume.html"), service.ResumeFormatHTML)
+		case strings.HasSuffix(path, "/resumes") && r.Method == http.MethodGet:
+			resumeVersionHandler.List(w, r, strings.TrimSuffix(path, "/resumes"))
+		case strings.HasSuffix(path, "/resumes") && r.Method == http.MethodPost:
+			resumeVersionHandler.Upload(w, r, strings.TrimSuffix(path, "/resumes"))
+		case strings.Contains(path, "/resumes/") && strings.HasSuffix(path, "/primary") && r.Method == http.MethodPost:
+			userID, resumeID, _ := strings.Cut(strings.TrimSuffix(path, "/primary"), "/resumes/")
+			resumeVersionHandler.SetPrimary(w, r, userID, resumeID)
+		case strings.Contains(path, "/resumes/") && strings.HasSuffix(path, "/download") && r.Method == http.MethodGet:
+			userID, resumeID, _ := strings.Cut(strings.TrimSuffix(path, "/download"), "/resumes/")
+			resumeVersionHandler.Download(w, r, userID, resumeID)
+		case strings.HasSuffix(path, "/resume/preview") && r.Method == http.MethodPost:
+			resumeApplyHandler.Preview(w, r, strings.TrimSuffix(path, "/resume/preview"))
+		case strings.HasSuffix(path, "/resume/apply") && r.Method == http.MethodPost:
+			resumeApplyHandler.Apply(w, r, strings.TrimSuffix(path, "/resume/apply"))
+		case strings.HasSuffix(path, "/resume/report") && r.Method == http.MethodGet:
+			resumeReportHandler.Report(w, r, strings.TrimSuffix(path, "/resume/report"))
+		case r.Method == http.MethodGet:
+			controller.GetUserByID(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
 
 	// Job APIs
 	jobService := &service.JobService{DB: db}
-	jobController := &controller.JobController{Service: jobService}
+	if err := jobService.BackfillLocations(); err != nil {
+		log.Printf("⚠️ Location backfill failed: %v", err)
+	}
+	rateService := &service.ExchangeRateService{DB: db}
+	if err := rateService.SeedDefaults(); err != nil {
+		log.Printf("⚠️ Exchange rate seeding failed: %v", err)
+	}
+	skillService := &service.SkillService{DB: db}
+	if err := skillService.SeedDefaults(); err != nil {
+		log.Printf("⚠️ Skill taxonomy seeding failed: %v", err)
+	}
+	service.UseSkillTaxonomy(skillService)
+	analyticsService := service.NewAnalyticsService(db)
+	onShutdown(analyticsService.Close)
+	referralService := &service.ReferralService{
+		DB:            db,
+		RewardStage:   config.AppConfig.ReferralRewardStage,
+		RewardCredits: config.AppConfig.ReferralRewardCredits,
+	}
+	applicationService := &service.ApplicationService{DB: db, Referrals: referralService}
+	jobController := &controller.JobController{Service: jobService, Analytics: analyticsService, Rates: rateService, Applications: applicationService}
+	applicationController := &controller.ApplicationController{Service: applicationService, Jobs: jobService}
+	referralController := &controller.ReferralController{Service: referralService, Jobs: jobService}
+	rateController := &controller.ExchangeRateController{Service: rateService}
+	bulkImportService := service.NewBulkImportService(db, config.AppConfig.BulkImportConcurrency,
+		config.AppConfig.BulkImportMaxFiles, config.AppConfig.ResumeParserMode)
+	onShutdown(bulkImportService.Close)
+	bulkImportController := &controller.BulkImportController{Service: bulkImportService, Tenants: tenantService}
+	jobDescriptionHandler := &handler.JobDescriptionHandler{Jobs: jobService, Rates: rateService, Tenants: tenantService}
 
 	// /jobs - POST: Create job | GET: List all jobs
 	mux.HandleFunc("/jobs", func(w http.ResponseWriter, r *http.Request) {
@@ -43,11 +160,66 @@ func RegisterRoutes(db *gorm.DB) http.Handler {
 		path := strings.TrimPrefix(r.URL.Path, "/jobs/")
 
 		switch {
+		// POST /jobs/parse?recruiter_id= - job description document to draft job
+		case path == "parse" && r.Method == http.MethodPost:
+			jobDescriptionHandler.Parse(w, r)
+			return
+
+		// POST /jobs/{jobID}/publish
+		case strings.HasSuffix(path, "/publish") && r.Method == http.MethodPost:
+			jobController.PublishJob(w, r, strings.TrimSuffix(path, "/publish"))
+			return
+
 		// GET /jobs/{jobID}/suggestions
 		case strings.HasSuffix(path, "/suggestions") && r.Method == http.MethodGet:
 			jobController.GetAISuggestions(w, r)
 			return
 
+		// POST /jobs/{jobID}/events
+		case strings.HasSuffix(path, "/events") && r.Method == http.MethodPost:
+			jobController.TrackJobEvent(w, r, strings.TrimSuffix(path, "/events"))
+			return
+
+		// GET|POST /jobs/{jobID}/questions
+		case strings.HasSuffix(path, "/questions") && r.Method == http.MethodGet:
+			applicationController.GetQuestions(w, r, strings.TrimSuffix(path, "/questions"))
+			return
+		case strings.HasSuffix(path, "/questions") && r.Method == http.MethodPost:
+			applicationController.SetQuestions(w, r, strings.TrimSuffix(path, "/questions"))
+			return
+
+		// POST /jobs/{jobID}/apply
+		case strings.HasSuffix(path, "/apply") && r.Method == http.MethodPost:
+			applicationController.Apply(w, r, strings.TrimSuffix(path, "/apply"))
+			return
+
+		// GET /jobs/{jobID}/applicants
+		case strings.HasSuffix(path, "/applicants") && r.Method == http.MethodGet:
+			applicationController.GetApplicants(w, r, strings.TrimSuffix(path, "/applicants"))
+			return
+
+		// GET /jobs/recruiter/{recruiterID}/analytics
+		case strings.HasPrefix(path, "recruiter/") && strings.HasSuffix(path, "/analytics") && r.Method == http.MethodGet:
+			recruiterID := strings.TrimSuffix(strings.TrimPrefix(path, "recruiter/"), "/analytics")
+			jobController.GetRecruiterAnalytics(w, r, recruiterID)
+			return
+
+		// POST /jobs/recruiter/{recruiterID}/imports | GET /jobs/recruiter/{recruiterID}/imports/{importID}
+		case strings.HasPrefix(path, "recruiter/") && strings.HasSuffix(path, "/imports") && r.Method == http.MethodPost:
+			recruiterID := strings.TrimSuffix(strings.TrimPrefix(path, "recruiter/"), "/imports")
+			bulkImportController.ImportResumes(w, r, recruiterID)
+			return
+		case strings.HasPrefix(path, "recruiter/") && strings.Contains(path, "/imports/") && r.Method == http.MethodGet:
+			recruiterID, importID, _ := strings.Cut(strings.TrimPrefix(path, "recruiter/"), "/imports/")
+			bulkImportController.GetImport(w, r, recruiterID, importID)
+			return
+
+		// GET /jobs/recruiter/{recruiterID}/talent-pool
+		case strings.HasPrefix(path, "recruiter/") && strings.HasSuffix(path, "/talent-pool") && r.Method == http.MethodGet:
+			recruiterID := strings.TrimSuffix(strings.TrimPrefix(path, "recruiter/"), "/talent-pool")
+			bulkImportController.GetTalentPool(w, r, recruiterID)
+			return
+
 		// GET /jobs/id/{jobID}
 		case strings.HasPrefix(path, "id/") && r.Method == http.MethodGet:
 			jobID := strings.TrimPrefix(path, "id/")
@@ -65,22 +237,135 @@ func RegisterRoutes(db *gorm.DB) http.Handler {
 		}
 	})
 
-	// AI Suggestions for a particular user
+	// POST /applications/{applicationID}/stage
+	mux.HandleFunc("/applications/", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/applications/")
+		if strings.HasSuffix(path, "/stage") && r.Method == http.MethodPost {
+			applicationController.UpdateStage(w, r, strings.TrimSuffix(path, "/stage"))
+			return
+		}
+		http.NotFound(w, r)
+	})
+
+	// Referrals: POST /referrals | GET /referrals/{code}
+	mux.HandleFunc("/referrals", method("POST", referralController.CreateReferral))
+	mux.HandleFunc("/referrals/", method("GET", func(w http.ResponseWriter, r *http.Request) {
+		referralController.OpenReferral(w, r, strings.TrimPrefix(r.URL.Path, "/referrals/"))
+	}))
+
+	// AI Suggestions and referral dashboard for a particular user
 	mux.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
 		if strings.HasSuffix(r.URL.Path, "/suggestions") && r.Method == http.MethodGet {
 			userID := strings.TrimPrefix(strings.TrimSuffix(r.URL.Path, "/suggestions"), "/users/")
 			jobController.GetUserAISuggestions(w, r, userID)
 			return
 		}
+		if strings.HasSuffix(r.URL.Path, "/referrals") && r.Method == http.MethodGet {
+			userID := strings.TrimPrefix(strings.TrimSuffix(r.URL.Path, "/referrals"), "/users/")
+			referralController.GetReferralDashboard(w, r, userID)
+			return
+		}
+	})
+
+	// Per-tenant settings: GET|PUT /tenants/{tenantID}/settings
+	tenantController := &controller.TenantController{Service: tenantService}
+	mux.HandleFunc("/tenants/", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/tenants/")
+		tenantID, ok := strings.CutSuffix(path, "/settings")
+		switch {
+		case !ok || tenantID == "" || strings.Contains(tenantID, "/"):
+			http.NotFound(w, r)
+		case r.Method == http.MethodGet:
+			tenantController.GetSettings(w, r, tenantID)
+		case r.Method == http.MethodPut:
+			tenantController.UpdateSettings(w, r, tenantID)
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Exchange rates used to normalize salaries (fiat and tokens)
+	mux.HandleFunc("/exchange-rates", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			rateController.GetExchangeRates(w, r)
+		case http.MethodPost:
+			rateController.UpsertExchangeRate(w, r)
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Skill taxonomy: GET /skills | GET /skills/{name}
+	// Admin: PUT|DELETE /admin/skills/{name} | GET /admin/unknown-skills | DELETE /admin/unknown-skills/{name}
+	// Names are path-escaped, e.g. /skills/CI%2FCD
+	skillController := &controller.SkillController{Service: skillService}
+	mux.HandleFunc("/skills", method("GET", skillController.ListSkills))
+	mux.HandleFunc("/skills/", method("GET", func(w http.ResponseWriter, r *http.Request) {
+		name, ok := skillName(r, "/skills/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		skillController.GetSkill(w, r, name)
+	}))
+	mux.HandleFunc("/admin/skills/", func(w http.ResponseWriter, r *http.Request) {
+		name, ok := skillName(r, "/admin/skills/")
+		switch {
+		case !ok:
+			http.NotFound(w, r)
+		case r.Method == http.MethodPut:
+			skillController.UpsertSkill(w, r, name)
+		case r.Method == http.MethodDelete:
+			skillController.DeleteSkill(w, r, name)
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
 	})
+	mux.HandleFunc("/admin/unknown-skills", method("GET", skillController.ListUnknownSkills))
+	mux.HandleFunc("/admin/unknown-skills/", method("DELETE", func(w http.ResponseWriter, r *http.Request) {
+		name, ok := skillName(r, "/admin/unknown-skills/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		skillController.DismissUnknownSkill(w, r, name)
+	}))
 
 	mux.HandleFunc("/credit/", jobController.GetUserCredit) // ✅ CORRECT
 
 	mux.HandleFunc("/api/verify-payment", method("POST", controller.VerifyPaymentHandler))
 
+	// Parse cache hits and misses (admin)
+	mux.HandleFunc("/admin/parse-cache", method("GET", controller.GetParseCacheMetrics))
+
 	return middleware.CORS(mux)
 }
 
+// shutdownHooks release background workers once the HTTP server has stopped
+var shutdownHooks []func()
+
+func onShutdown(fn func()) {
+	shutdownHooks = append(shutdownHooks, fn)
+}
+
+// Shutdown runs the registered hooks, e.g. flushing buffered analytics events
+func Shutdown() {
+	for _, fn := range shutdownHooks {
+		fn()
+	}
+}
+
+// skillName returns the path-escaped skill name after prefix.
+func skillName(r *http.Request, prefix string) (string, bool) {
+	escaped, ok := strings.CutPrefix(r.URL.EscapedPath(), prefix)
+	if !ok || escaped == "" || strings.Contains(escaped, "/") {
+		return "", false
+	}
+	name, err := url.PathUnescape(escaped)
+	return name, err == nil && strings.TrimSpace(name) != ""
+}
+
 // method ensures only a specific HTTP method is allowed
 func method(method string, handlerFunc http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"gorm.io/gorm"
+)
+
+const (
+	analyticsBufferSize    = 4096
+	analyticsBatchSize     = 500
+	analyticsFlushInterval = 5 * time.Second
+	analyticsDedupeWindow  = 30 * time.Minute
+)
+
+var botUserAgent = regexp.MustCompile(`(?i)bot|crawl|spider|slurp|scrape|fetch|preview|monitor|headless|phantom|curl|wget|python-requests|python-urllib|go-http-client|java/|okhttp|axios|node-fetch|httpclient|lighthouse|pingdom|uptime`)
+
+// AnalyticsService buffers job engagement events in memory and writes them
+// to the database in batches, so tracking never blocks a request on SQLite.
+type AnalyticsService struct {
+	DB *gorm.DB
+
+	events chan model.JobEvent
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	mu       sync.Mutex
+	lastSeen map[string]time.Time // job/type/visitor -> last recorded event
+}
+
+func NewAnalyticsService(db *gorm.DB) *AnalyticsService {
+	s := &AnalyticsService{
+		DB:       db,
+		events:   make(chan model.JobEvent, analyticsBufferSize),
+		done:     make(chan struct{}),
+		lastSeen: make(map[string]time.Time),
+	}
+	s.wg.Add(1)
+	go s.run()
+	return s
+}
+
+// IsBot reports whether a user agent looks like a crawler, script or monitor.
+// Requests without a user agent are treated as bots.
+func IsBot(userAgent string) bool {
+	return userAgent == "" || botUserAgent.MatchString(userAgent)
+}
+
+// VisitorID derives an anonymous, stable visitor key from the client IP and user agent.
+func VisitorID(ip, userAgent string) string {
+	sum := sha256.Sum256([]byte(ip + "|" + userAgent))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Record queues an event for writing. Events from bots, repeats from the same
+// visitor within the dedupe window, and events arriving while the buffer is
+// full are dropped.
+func (s *AnalyticsService) Record(event model.JobEvent, userAgent string) {
+	if IsBot(userAgent) {
+		return
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	key := strconv.FormatUint(uint64(event.JobID), 10) + "|" + event.Type + "|" + event.VisitorID
+	s.mu.Lock()
+	if last, ok := s.lastSeen[key]; ok && event.CreatedAt.Sub(last) < analyticsDedupeWindow {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSeen[key] = event.CreatedAt
+	s.mu.Unlock()
+
+	select {
+	case s.events <- event:
+	default:
+		log.Println("⚠️ Analytics buffer full, dropping event")
+	}
+}
+
+// Close flushes any buffered events and stops the background writer.
+func (s *AnalyticsService) Close() {
+	close(s.done)
+	s.wg.Wait()
+}
+
+func (s *AnalyticsService) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(analyticsFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]model.JobEvent, 0, analyticsBatchSize)
+	for {
+		select {
+		case e := <-s.events:
+			batch = append(batch, e)
+			if len(batch) >= analyticsBatchSize {
+				batch = s.flush(batch)
+			}
+		case <-ticker.C:
+			batch = s.flush(batch)
+			s.pruneSeen()
+		case <-s.done:
+			for {
+				select {
+				case e := <-s.events:
+					batch = append(batch, e)
+				default:
+					s.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *AnalyticsService) flush(batch []model.JobEvent) []model.JobEvent {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := s.DB.CreateInBatches(batch, analyticsBatchSize).Error; err != nil {
+		log.Printf("❌ Failed to write %d analytics events: %v", len(batch), err)
+	}
+	return batch[:0]
+}
+
+func (s *AnalyticsService) pruneSeen() {
+	cutoff := time.Now().Add(-analyticsDedupeWindow)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, t := range s.lastSeen {
+		if t.Before(cutoff) {
+			delete(s.lastSeen, k)
+		}
+	}
+}
+
+type dailyCount struct {
+	JobID uint
+	Type  string
+	Day   string
+	Total int
+}
+
+// RecruiterAnalytics returns per-job daily series, totals and conversion
+// rates for every job posted by the recruiter between from and to.
+// Applications are counted from job_applications rather than tracked events.
+func (s *AnalyticsService) RecruiterAnalytics(recruiterID string, from, to time.Time) (*model.RecruiterAnalyticsResponse, error) {
+	var jobs []model.Job
+	if err := s.DB.Where("recruiter_id = ?", recruiterID).Order("created_at desc").Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	resp := &model.RecruiterAnalyticsResponse{
+		RecruiterID: recruiterID,
+		From:        from.UTC().Format("2006-01-02"),
+		To:          to.UTC().Format("2006-01-02"),
+		Jobs:        []model.JobAnalytics{},
+	}
+	if len(jobs) == 0 {
+		return resp, nil
+	}
+
+	jobIDs := make([]uint, len(jobs))
+	for i, j := range jobs {
+		jobIDs[i] = j.ID
+	}
+
+	var eventCounts []dailyCount
+	if err := s.DB.Model(&model.JobEvent{}).
+		Select("job_id, type, date(created_at) AS day, COUNT(*) AS total").
+		Where("job_id IN ? AND created_at BETWEEN ? AND ?", jobIDs, from, to).
+		Group("job_id, type, day").
+		Scan(&eventCounts).Error; err != nil {
+		return nil, err
+	}
+
+	var applicationCounts []dailyCount
+	if err := s.DB.Model(&model.JobApplication{}).
+		Select("job_id, ? AS type, date(created_at) AS day, COUNT(*) AS total", model.EventApplication).
+		Where("job_id IN ? AND created_at BETWEEN ? AND ?", jobIDs, from, to).
+		Group("job_id, day").
+		Scan(&applicationCounts).Error; err != nil {
+		return nil, err
+	}
+
+	// job -> day -> counts
+	byJob := make(map[uint]map[string]*model.JobEventCounts)
+	for _, c := range append(eventCounts, applicationCounts...) {
+		days, ok := byJob[c.JobID]
+		if !ok {
+			days = make(map[string]*model.JobEventCounts)
+			byJob[c.JobID] = days
+		}
+		counts, ok := days[c.Day]
+		if !ok {
+			counts = &model.JobEventCounts{}
+			days[c.Day] = counts
+		}
+		addCount(counts, c.Type, c.Total)
+	}
+
+	for _, job := range jobs {
+		ja := model.JobAnalytics{JobID: job.ID, Title: job.Title, Series: []model.JobAnalyticsPoint{}}
+		for day, counts := range byJob[job.ID] {
+			ja.Series = append(ja.Series, model.JobAnalyticsPoint{Date: day, JobEventCounts: *counts})
+			sumCounts(&ja.Totals, *counts)
+		}
+		sort.Slice(ja.Series, func(i, k int) bool { return ja.Series[i].Date < ja.Series[k].Date })
+		ja.Conversion = conversionRates(ja.Totals)
+
+		sumCounts(&resp.Totals, ja.Totals)
+		resp.Jobs = append(resp.Jobs, ja)
+	}
+
+	return resp, nil
+}
+
+func addCount(c *model.JobEventCounts, eventType string, n int) {
+	switch eventType {
+	case model.EventView:
+		c.Views += n
+	case model.EventDetailOpen:
+		c.DetailOpens += n
+	case model.EventApplyClick:
+		c.ApplyClicks += n
+	case model.EventApplication:
+		c.Applications += n
+	}
+}
+
+func sumCounts(dst *model.JobEventCounts, src model.JobEventCounts) {
+	dst.Views += src.Views
+	dst.DetailOpens += src.DetailOpens
+	dst.ApplyClicks += src.ApplyClicks
+	dst.Applications += src.Applications
+}
+
+func conversionRates(c model.JobEventCounts) model.JobConversionRates {
+	return model.JobConversionRates{
+		ViewToDetail:       ratio(c.DetailOpens, c.Views),
+		DetailToApplyClick: ratio(c.ApplyClicks, c.DetailOpens),
+		ApplyClickToApply:  ratio(c.Applications, c.ApplyClicks),
+		ViewToApplication:  ratio(c.Applications, c.Views),
+	}
+}
+
+func ratio(num, den int) float64 {
+	if den == 0 {
+		return 0
+	}
+	return math.Round(float64(num)/float64(den)*10000) / 10000
+}
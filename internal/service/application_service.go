@@ -0,0 +1,380 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAlreadyApplied      = errors.New("user has already applied to this job")
+	ErrInvalidAnswer       = errors.New("invalid screening answer")
+	ErrInvalidStage        = errors.New("invalid stage. Must be applied, screening, interview, offer, hired or rejected")
+	ErrApplicationNotFound = errors.New("application not found")
+	ErrJobNotPublished     = errors.New("job is a draft and not open to applications")
+	ErrQuestionsLocked     = errors.New("screening questions cannot be replaced once the job has applications")
+	ErrNotJobRecruiter     = errors.New("only the job's recruiter can do this")
+)
+
+type ApplicationService struct {
+	DB        *gorm.DB
+	Referrals *ReferralService
+}
+
+// ApplyInput is what an applicant submits along with an application.
+type ApplyInput struct {
+	Answers      []AnswerInput `json:"answers"`
+	ReferralCode string        `json:"referral_code"`
+	ResumeID     *uuid.UUID    `json:"resume_id"` // defaults to the applicant's primary resume
+}
+
+// AnswerInput is an applicant's answer to one screening question.
+type AnswerInput struct {
+	QuestionID uint   `json:"question_id"`
+	Value      string `json:"value"`
+}
+
+// AnswerFilter matches applicants by their answer to one question.
+// Equals is compared case-insensitively; Min/Max apply to numeric answers.
+type AnswerFilter struct {
+	QuestionID uint
+	Equals     string
+	Min        *float64
+	Max        *float64
+}
+
+// ---------- Screening Questions ----------
+
+func (s *ApplicationService) GetQuestions(jobID uint) ([]model.ScreeningQuestion, error) {
+	var questions []model.ScreeningQuestion
+	err := s.DB.Where("job_id = ?", jobID).Order("position, id").Find(&questions).Error
+	return questions, err
+}
+
+// ReplaceQuestions validates and stores the job's screening questions,
+// replacing any previous set. Once the job has applications the questions
+// are locked with ErrQuestionsLocked, since their answers refer to them.
+func (s *ApplicationService) ReplaceQuestions(jobID uint, questions []model.ScreeningQuestion) ([]model.ScreeningQuestion, error) {
+	for i := range questions {
+		q := &questions[i]
+		q.ID = 0
+		q.JobID = jobID
+		q.Position = i
+		if err := validateQuestion(q); err != nil {
+			return nil, fmt.Errorf("question %d: %w", i+1, err)
+		}
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var applications int64
+		if err := tx.Model(&model.JobApplication{}).Where("job_id = ?", jobID).Count(&applications).Error; err != nil {
+			return err
+		}
+		if applications > 0 {
+			return ErrQuestionsLocked
+		}
+		if err := tx.Where("job_id = ?", jobID).Delete(&model.ScreeningQuestion{}).Error; err != nil {
+			return err
+		}
+		if len(questions) == 0 {
+			return nil
+		}
+		return tx.Create(&questions).Error
+	})
+	return questions, err
+}
+
+func validateQuestion(q *model.ScreeningQuestion) error {
+	q.Prompt = strings.TrimSpace(q.Prompt)
+	if q.Prompt == "" {
+		return errors.New("prompt is required")
+	}
+
+	switch q.Type {
+	case model.QuestionYesNo:
+		q.Options = []string{"yes", "no"}
+		for i, a := range q.KnockoutAnswers {
+			a = strings.ToLower(strings.TrimSpace(a))
+			if a != "yes" && a != "no" {
+				return errors.New("knockout answers for yes_no must be yes or no")
+			}
+			q.KnockoutAnswers[i] = a
+		}
+	case model.QuestionMultipleChoice:
+		if len(q.Options) < 2 {
+			return errors.New("multiple_choice needs at least two options")
+		}
+		for _, a := range q.KnockoutAnswers {
+			if !containsFold(q.Options, a) {
+				return fmt.Errorf("knockout answer %q is not one of the options", a)
+			}
+		}
+	case model.QuestionNumeric:
+		if q.MinValue != nil && q.MaxValue != nil && *q.MinValue > *q.MaxValue {
+			return errors.New("min_value must not exceed max_value")
+		}
+	case model.QuestionText:
+		if len(q.KnockoutAnswers) > 0 || q.MinValue != nil || q.MaxValue != nil {
+			return errors.New("text questions cannot have knockout rules")
+		}
+	default:
+		return errors.New("type must be yes_no, multiple_choice, numeric or text")
+	}
+
+	if q.Type != model.QuestionNumeric && (q.MinValue != nil || q.MaxValue != nil) {
+		return errors.New("min_value and max_value are only valid for numeric questions")
+	}
+	return nil
+}
+
+// evaluateAnswer normalizes an answer and reports whether it triggers a knockout rule.
+func evaluateAnswer(q model.ScreeningQuestion, value string) (string, bool, error) {
+	value = strings.TrimSpace(value)
+
+	switch q.Type {
+	case model.QuestionYesNo:
+		v := strings.ToLower(value)
+		switch v {
+		case "yes", "y", "true":
+			v = "yes"
+		case "no", "n", "false":
+			v = "no"
+		default:
+			return "", false, fmt.Errorf("%w: %q expects yes or no", ErrInvalidAnswer, q.Prompt)
+		}
+		return v, containsFold(q.KnockoutAnswers, v), nil
+
+	case model.QuestionMultipleChoice:
+		for _, opt := range q.Options {
+			if strings.EqualFold(opt, value) {
+				return opt, containsFold(q.KnockoutAnswers, opt), nil
+			}
+		}
+		return "", false, fmt.Errorf("%w: %q expects one of %s", ErrInvalidAnswer, q.Prompt, strings.Join(q.Options, ", "))
+
+	case model.QuestionNumeric:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", false, fmt.Errorf("%w: %q expects a number", ErrInvalidAnswer, q.Prompt)
+		}
+		knockedOut := (q.MinValue != nil && n < *q.MinValue) || (q.MaxValue != nil && n > *q.MaxValue)
+		return strconv.FormatFloat(n, 'f', -1, 64), knockedOut, nil
+	}
+
+	return value, false, nil
+}
+
+// ---------- Applications ----------
+
+// Apply creates an application with its screening answers, attributed to a
+// referral when a code is given. Missing required answers and answers of the
+// wrong shape are rejected with ErrInvalidAnswer, and a resume version of
+// another user with ErrResumeNotFound, and draft jobs with ErrJobNotPublished.
+func (s *ApplicationService) Apply(jobID uint, userID uuid.UUID, input ApplyInput) (*model.JobApplication, error) {
+	var job model.Job
+	if err := s.DB.Select("status").First(&job, jobID).Error; err != nil {
+		return nil, err
+	}
+	if job.Status == model.JobStatusDraft {
+		return nil, ErrJobNotPublished
+	}
+
+	questions, err := s.GetQuestions(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	given := make(map[uint]string, len(input.Answers))
+	for _, in := range input.Answers {
+		given[in.QuestionID] = in.Value
+	}
+
+	app := &model.JobApplication{JobID: jobID, UserID: userID, Stage: model.StageApplied}
+	for _, q := range questions {
+		raw, ok := given[q.ID]
+		delete(given, q.ID)
+		if !ok || strings.TrimSpace(raw) == "" {
+			if q.Required {
+				return nil, fmt.Errorf("%w: %q is required", ErrInvalidAnswer, q.Prompt)
+			}
+			continue
+		}
+
+		value, knockedOut, err := evaluateAnswer(q, raw)
+		if err != nil {
+			return nil, err
+		}
+		app.KnockedOut = app.KnockedOut || knockedOut
+		app.Answers = append(app.Answers, model.ApplicationAnswer{QuestionID: q.ID, Value: value, KnockedOut: knockedOut})
+	}
+	for id := range given {
+		// Anything left over refers to a question of another job
+		return nil, fmt.Errorf("%w: question %d does not belong to this job", ErrInvalidAnswer, id)
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.JobApplication{}).Where("job_id = ? AND user_id = ?", jobID, userID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrAlreadyApplied
+		}
+
+		if input.ReferralCode != "" && s.Referrals != nil {
+			ref, err := s.Referrals.resolveForApplication(tx, input.ReferralCode, jobID, userID)
+			if err != nil {
+				return err
+			}
+			app.ReferralID = &ref.ID
+		}
+
+		if input.ResumeID != nil {
+			resume, err := findResume(tx, userID, *input.ResumeID)
+			if err != nil {
+				return err
+			}
+			app.ResumeID = &resume.ID
+		} else {
+			var primary model.Resume
+			if err := tx.Where("user_id = ? AND is_primary = ?", userID, true).Limit(1).Find(&primary).Error; err != nil {
+				return err
+			}
+			if primary.ID != uuid.Nil {
+				app.ResumeID = &primary.ID
+			}
+		}
+
+		return tx.Create(app).Error
+	})
+	if err != nil {
+		// A concurrent application may have taken the unique index first
+		var count int64
+		if s.DB.Model(&model.JobApplication{}).Where("job_id = ? AND user_id = ?", jobID, userID).Count(&count).Error == nil && count > 0 {
+			return nil, ErrAlreadyApplied
+		}
+		return nil, err
+	}
+	return app, nil
+}
+
+// UpdateStage moves an application through the pipeline and grants any
+// referral reward in the same transaction. Only the job's recruiter may move
+// it; anyone else gets ErrNotJobRecruiter.
+func (s *ApplicationService) UpdateStage(applicationID uint, recruiterID uuid.UUID, stage string) (*model.JobApplication, error) {
+	if stage != model.StageRejected && model.StageRank(stage) < 0 {
+		return nil, ErrInvalidStage
+	}
+
+	var app model.JobApplication
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("Job").First(&app, applicationID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrApplicationNotFound
+			}
+			return err
+		}
+		if app.Job.RecruiterID != recruiterID {
+			return ErrNotJobRecruiter
+		}
+		app.Stage = stage
+		if err := tx.Model(&app).Update("stage", stage).Error; err != nil {
+			return err
+		}
+		if s.Referrals != nil {
+			return s.Referrals.grantReward(tx, &app)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+// GetApplicants lists a job's applications with users and answers, keeping
+// only those matching every answer filter.
+func (s *ApplicationService) GetApplicants(jobID uint, filters []AnswerFilter, excludeKnockedOut bool) ([]model.JobApplication, error) {
+	query := s.DB.Preload("User.Education").Preload("User.Experience").Preload("Answers").
+		Where("job_id = ?", jobID).Order("created_at desc")
+	if excludeKnockedOut {
+		query = query.Where("knocked_out = ?", false)
+	}
+
+	var apps []model.JobApplication
+	if err := query.Find(&apps).Error; err != nil {
+		return nil, err
+	}
+	if len(filters) == 0 {
+		return apps, nil
+	}
+
+	filtered := apps[:0]
+	for _, app := range apps {
+		if matchesAnswerFilters(app.Answers, filters) {
+			filtered = append(filtered, app)
+		}
+	}
+	return filtered, nil
+}
+
+func matchesAnswerFilters(answers []model.ApplicationAnswer, filters []AnswerFilter) bool {
+	byQuestion := make(map[uint]string, len(answers))
+	for _, a := range answers {
+		byQuestion[a.QuestionID] = a.Value
+	}
+
+	for _, f := range filters {
+		value, ok := byQuestion[f.QuestionID]
+		if !ok {
+			return false
+		}
+		if f.Equals != "" && !strings.EqualFold(value, f.Equals) {
+			return false
+		}
+		if f.Min != nil || f.Max != nil {
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil || (f.Min != nil && n < *f.Min) || (f.Max != nil && n > *f.Max) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ScreeningSummaries returns, per applicant of the job, a "Q: A" summary of
+// their screening answers for use in matching prompts.
+func (s *ApplicationService) ScreeningSummaries(jobID uint) (map[uuid.UUID]string, error) {
+	var apps []model.JobApplication
+	if err := s.DB.Preload("Answers.Question").Where("job_id = ?", jobID).Find(&apps).Error; err != nil {
+		return nil, err
+	}
+
+	summaries := make(map[uuid.UUID]string, len(apps))
+	for _, app := range apps {
+		var lines []string
+		for _, a := range app.Answers {
+			line := fmt.Sprintf("%s %s", a.Question.Prompt, a.Value)
+			if a.KnockedOut {
+				line += " (knockout)"
+			}
+			lines = append(lines, line)
+		}
+		summaries[app.UserID] = strings.Join(lines, "; ")
+	}
+	return summaries, nil
+}
+
+func containsFold(list []string, v string) bool {
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), strings.TrimSpace(v)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/utils"
+	"gorm.io/gorm"
+)
+
+// maxImportFileSize caps a single resume inside an archive
+const maxImportFileSize = 10 << 20
+
+var (
+	ErrRecruiterNotFound = errors.New("recruiter not found")
+	ErrInvalidArchive    = errors.New("not a valid ZIP archive")
+	ErrEmptyArchive      = errors.New("archive contains no files")
+	ErrTooManyFiles      = errors.New("archive contains too many files")
+	ErrImportNotFound    = errors.New("import not found")
+)
+
+// BulkImportService parses ZIP archives of resumes in the background and adds
+// the candidates to the recruiter's talent pool as draft profiles.
+type BulkImportService struct {
+	DB *gorm.DB
+
+	Concurrency int    // files parsed at once per import
+	MaxFiles    int    // files allowed per archive
+	Mode        string // resume parser mode
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	dbMu   sync.Mutex // serializes writes; parsing is what runs concurrently
+}
+
+func NewBulkImportService(db *gorm.DB, concurrency, maxFiles int, mode string) *BulkImportService {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &BulkImportService{
+		DB:          db,
+		Concurrency: max(concurrency, 1),
+		MaxFiles:    maxFiles,
+		Mode:        mode,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+
+	// Imports cut short by a restart are not resumed
+	var stale []model.BulkImport
+	if err := db.Where("status = ?", model.BulkImportRunning).Find(&stale).Error; err != nil {
+		log.Printf("⚠️ Failed to load interrupted imports: %v", err)
+	}
+	for i := range stale {
+		s.finishInterrupted(&stale[i], "interrupted by a server restart")
+	}
+	return s
+}
+
+// Start validates the archive, records one item per file and processes the
+// files in the background, withholding PII from Gemini when redactPII is set.
+// The service owns zipPath from here on.
+func (s *BulkImportService) Start(recruiterID uuid.UUID, zipPath, fileName string, redactPII bool) (*model.BulkImport, error) {
+	var recruiter model.User
+	if err := s.DB.Where("id = ? AND role = ?", recruiterID, "recruiter").First(&recruiter).Error; err != nil {
+		os.Remove(zipPath)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRecruiterNotFound
+		}
+		return nil, err
+	}
+
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		os.Remove(zipPath)
+		return nil, ErrInvalidArchive
+	}
+	var files []*zip.File
+	for _, f := range zr.File {
+		if isResumeEntry(f) {
+			files = append(files, f)
+		}
+	}
+	zr.Close()
+
+	switch {
+	case len(files) == 0:
+		os.Remove(zipPath)
+		return nil, ErrEmptyArchive
+	case s.MaxFiles > 0 && len(files) > s.MaxFiles:
+		os.Remove(zipPath)
+		return nil, fmt.Errorf("%w: %d files, at most %d allowed", ErrTooManyFiles, len(files), s.MaxFiles)
+	}
+
+	imp := model.BulkImport{RecruiterID: recruiterID, FileName: fileName, Status: model.BulkImportRunning,
+		RedactPII: redactPII, Total: len(files)}
+	for _, f := range files {
+		imp.Items = append(imp.Items, model.BulkImportItem{FileName: f.Name, Status: model.ImportItemPending})
+	}
+	if err := s.DB.Create(&imp).Error; err != nil {
+		os.Remove(zipPath)
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go s.run(imp, zipPath)
+	return &imp, nil
+}
+
+// isResumeEntry skips directories and the metadata files archivers add.
+func isResumeEntry(f *zip.File) bool {
+	base := path.Base(f.Name)
+	return !f.FileInfo().IsDir() && !strings.HasPrefix(f.Name, "__MACOSX/") &&
+		!strings.HasPrefix(base, ".") && base != "Thumbs.db" && base != "desktop.ini"
+}
+
+func (s *BulkImportService) Get(id uint) (*model.BulkImport, error) {
+	var imp model.BulkImport
+	err := s.DB.Preload("Items").First(&imp, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrImportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &imp, nil
+}
+
+// TalentPool lists the candidates in a recruiter's pool, newest first.
+func (s *BulkImportService) TalentPool(recruiterID uuid.UUID) ([]model.TalentPoolEntry, error) {
+	var entries []model.TalentPoolEntry
+	err := s.DB.Preload("User.Education").Preload("User.Experience").
+		Where("recruiter_id = ?", recruiterID).Order("created_at desc").Find(&entries).Error
+	return entries, err
+}
+
+// Close stops starting new files and waits for those being parsed.
+// Imports left unfinished are marked failed.
+func (s *BulkImportService) Close() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *BulkImportService) run(imp model.BulkImport, zipPath string) {
+	defer s.wg.Done()
+	defer os.Remove(zipPath)
+
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		s.finishInterrupted(&imp, "archive could not be reopened: "+err.Error())
+		return
+	}
+	defer zr.Close()
+
+	entries := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		entries[f.Name] = f
+	}
+
+	sem := make(chan struct{}, s.Concurrency)
+	var wg sync.WaitGroup
+	for i := range imp.Items {
+		select {
+		case sem <- struct{}{}:
+		case <-s.ctx.Done():
+		}
+		if s.ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(item *model.BulkImportItem) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.processItem(&imp, item, entries[item.FileName])
+		}(&imp.Items[i])
+	}
+	wg.Wait()
+
+	if s.ctx.Err() != nil {
+		s.finishInterrupted(&imp, "interrupted by server shutdown")
+		return
+	}
+
+	now := time.Now()
+	s.dbMu.Lock()
+	defer s.dbMu.Unlock()
+	if err := s.DB.Model(&model.BulkImport{}).Where("id = ?", imp.ID).
+		Updates(map[string]interface{}{"status": model.BulkImportCompleted, "completed_at": &now}).Error; err != nil {
+		log.Printf("❌ Failed to complete import %d: %v", imp.ID, err)
+	}
+	log.Printf("📦 Import %d finished: %d files", imp.ID, imp.Total)
+}
+
+func (s *BulkImportService) processItem(imp *model.BulkImport, item *model.BulkImportItem, f *zip.File) {
+	var (
+		result  *model.ParseResult
+		userID  uuid.UUID
+		created bool
+		err     error
+	)
+	if f == nil {
+		err = errors.New("file missing from archive")
+	} else {
+		result, err = s.parseEntry(f, ParseOptions{Mode: s.Mode, RedactPII: imp.RedactPII})
+	}
+	if err == nil {
+		s.dbMu.Lock()
+		userID, created, err = s.saveCandidate(imp, result)
+		s.dbMu.Unlock()
+	}
+
+	counter := "failed"
+	switch {
+	case err != nil:
+		item.Status, item.Error = model.ImportItemFailed, err.Error()
+	case created:
+		item.Status, counter = model.ImportItemCreated, "created"
+	default:
+		item.Status, counter = model.ImportItemLinked, "linked"
+	}
+	if err == nil {
+		item.UserID, item.Parser = &userID, result.Parser
+	}
+
+	s.dbMu.Lock()
+	defer s.dbMu.Unlock()
+	if err := s.DB.Model(item).Select("status", "error", "user_id", "parser").Updates(item).Error; err != nil {
+		log.Printf("❌ Failed to save import item %d: %v", item.ID, err)
+	}
+	if err := s.DB.Model(&model.BulkImport{}).Where("id = ?", imp.ID).Updates(map[string]interface{}{
+		"processed": gorm.Expr("processed + 1"),
+		counter:     gorm.Expr(counter + " + 1"),
+	}).Error; err != nil {
+		log.Printf("❌ Failed to update import %d progress: %v", imp.ID, err)
+	}
+}
+
+// parseEntry copies one archive entry to a temp file and parses it.
+func (s *BulkImportService) parseEntry(f *zip.File, opts ParseOptions) (*model.ParseResult, error) {
+	if f.UncompressedSize64 > maxImportFileSize {
+		return nil, fmt.Errorf("file is larger than %d MB", maxImportFileSize>>20)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	tmp, err := os.CreateTemp("", "import-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	// The header size can lie; stop copying at the limit regardless
+	n, err := io.Copy(tmp, io.LimitReader(rc, maxImportFileSize+1))
+	tmp.Close()
+	if err != nil {
+		return nil, err
+	}
+	if n > maxImportFileSize {
+		return nil, fmt.Errorf("file is larger than %d MB", maxImportFileSize>>20)
+	}
+
+	return ParseResume(tmp.Name(), opts)
+}
+
+// saveCandidate links the resume's candidate to the recruiter's pool. A
+// candidate whose email already has a profile is linked as is; anyone else
+// gets a new draft profile built from the resume.
+func (s *BulkImportService) saveCandidate(imp *model.BulkImport, result *model.ParseResult) (uuid.UUID, bool, error) {
+	resume := result.Resume
+	var userID uuid.UUID
+	created := false
+	skills := strings.Join(skillTaxonomy.Normalize(resume.Skills), ", ")
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		email := strings.ToLower(strings.TrimSpace(resume.Email))
+		var existing model.User
+		if email != "" {
+			err := tx.Where("LOWER(email) = ?", email).First(&existing).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		}
+
+		if existing.ID != uuid.Nil {
+			userID = existing.ID
+		} else {
+			if email == "" {
+				// Email is unique and required, so draft profiles without one
+				// get an undeliverable placeholder (.invalid is reserved)
+				email = "draft-" + uuid.NewString() + "@drafts.invalid"
+			}
+			user := model.User{
+				Email:          email,
+				FullName:       resume.FullName,
+				Title:          resume.Title,
+				Location:       resume.Location,
+				Geo:            utils.ResolveLocation(resume.Location),
+				Phone:          resume.Phone,
+				CurrentCompany: resume.CurrentCompany,
+				LinkedIn:       resume.LinkedIn,
+				GitHub:         resume.GitHub,
+				Portfolio:      resume.Portfolio,
+				Skills:         skills,
+				Language:       result.Language,
+				Role:           "applicant",
+				Status:         model.UserStatusDraft,
+			}
+			if err := tx.Create(&user).Error; err != nil {
+				return fmt.Errorf("create draft profile: %w", err)
+			}
+			for _, e := range resume.Education {
+				edu := model.Education{UserID: user.ID, Institution: e.Institution, Location: e.Location, Degree: e.Degree, GPA: e.GPA, Years: e.Years, Period: utils.ParseDateRange(e.Years)}
+				if err := tx.Create(&edu).Error; err != nil {
+					return fmt.Errorf("add education: %w", err)
+				}
+			}
+			for _, e := range resume.Experience {
+				exp := model.Experience{UserID: user.ID, Company: e.Company, Location: e.Location, Title: e.Title, Years: e.Years, Period: utils.ParseDateRange(e.Years), Description: e.Description}
+				if err := tx.Create(&exp).Error; err != nil {
+					return fmt.Errorf("add experience: %w", err)
+				}
+			}
+			userID, created = user.ID, true
+		}
+
+		entry := model.TalentPoolEntry{RecruiterID: imp.RecruiterID, UserID: userID, Source: model.TalentSourceBulkImport, ImportID: &imp.ID}
+		return tx.Where("recruiter_id = ? AND user_id = ?", imp.RecruiterID, userID).FirstOrCreate(&entry).Error
+	})
+	return userID, created, err
+}
+
+// finishInterrupted fails an import's pending items and the import itself.
+func (s *BulkImportService) finishInterrupted(imp *model.BulkImport, reason string) {
+	s.dbMu.Lock()
+	defer s.dbMu.Unlock()
+
+	res := s.DB.Model(&model.BulkImportItem{}).Where("import_id = ? AND status = ?", imp.ID, model.ImportItemPending).
+		Updates(map[string]interface{}{"status": model.ImportItemFailed, "error": reason})
+	if res.Error != nil {
+		log.Printf("❌ Failed to update items of import %d: %v", imp.ID, res.Error)
+	}
+
+	now := time.Now()
+	if err := s.DB.Model(&model.BulkImport{}).Where("id = ?", imp.ID).Updates(map[string]interface{}{
+		"status":       model.BulkImportFailed,
+		"processed":    gorm.Expr("processed + ?", res.RowsAffected),
+		"failed":       gorm.Expr("failed + ?", res.RowsAffected),
+		"completed_at": &now,
+	}).Error; err != nil {
+		log.Printf("❌ Failed to mark import %d as failed: %v", imp.ID, err)
+	}
+}
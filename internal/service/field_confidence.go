@@ -0,0 +1,187 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/utils"
+)
+
+// Confidence of a value before it is checked against the document. Gemini
+// reads context the heuristic parser cannot, but may also rephrase.
+const (
+	geminiFieldConfidence    = 0.8
+	heuristicFieldConfidence = 0.7
+)
+
+// fieldScoringVersion is part of the parse cache fingerprint, so cached
+// results are scored again when scoring changes.
+const fieldScoringVersion = "1"
+
+// descriptionPrefix is how much of a description that is not in the
+// document as a whole is looked up to find where it starts
+const descriptionPrefix = 60
+
+// fieldScorer scores the values of one parsed resume against its document.
+type fieldScorer struct {
+	doc    *utils.Document
+	base   float64
+	fields map[string]model.FieldConfidence
+}
+
+// scoreFields fills in result.Fields and result.LowConfidence: each value is
+// looked up in the document, where a value found as written is trusted
+// more, and checked for the shape its field should have.
+func scoreFields(result *model.ParseResult, doc *utils.Document) {
+	s := &fieldScorer{doc: doc, base: geminiFieldConfidence, fields: map[string]model.FieldConfidence{}}
+	if result.Parser == parserHeuristic {
+		s.base = heuristicFieldConfidence
+	}
+	resume := result.Resume
+
+	s.score("full_name", resume.FullName, 0, nil)
+	s.score("title", resume.Title, 0, nil)
+	s.score("location", resume.Location, 0, nil)
+	s.score("email", resume.Email, 0, validEmail)
+	s.score("phone", resume.Phone, 0, validPhone)
+	s.score("current_company", resume.CurrentCompany, 0, nil)
+	s.score("linkedin", resume.LinkedIn, 0, matchesAll(linkedInPattern))
+	s.score("github", resume.GitHub, 0, matchesAll(gitHubPattern))
+	s.score("portfolio", resume.Portfolio, 0, matchesAll(urlPattern))
+	for i, skill := range resume.Skills {
+		s.score(fmt.Sprintf("skills[%d]", i), skill, 0, nil)
+	}
+
+	// Entries are in document order, so each is looked for after the last.
+	// An entry is found by its company or institution, and its other fields
+	// from the line before that on.
+	from := 0
+	for i, e := range resume.Experience {
+		path := fmt.Sprintf("experience[%d].", i)
+		anchor := s.score(path+"company", e.Company, from, nil)
+		near := s.near(anchor, from)
+		anchor = earliest(anchor, s.score(path+"title", e.Title, near, nil))
+		s.score(path+"location", e.Location, near, nil)
+		s.score(path+"years", e.Years, near, validPeriod(e.Period))
+		s.score(path+"description", e.Description, near, nil)
+		if anchor >= 0 {
+			from = anchor + 1
+		}
+	}
+	from = 0
+	for i, e := range resume.Education {
+		path := fmt.Sprintf("education[%d].", i)
+		anchor := s.score(path+"institution", e.Institution, from, nil)
+		near := s.near(anchor, from)
+		anchor = earliest(anchor, s.score(path+"degree", e.Degree, near, nil))
+		s.score(path+"location", e.Location, near, nil)
+		s.score(path+"gpa", e.GPA, near, nil)
+		s.score(path+"years", e.Years, near, validPeriod(e.Period))
+		if anchor >= 0 {
+			from = anchor + 1
+		}
+	}
+
+	result.Fields = s.fields
+	result.LowConfidence = nil
+	for path, f := range s.fields {
+		if f.Confidence < model.LowFieldConfidence {
+			result.LowConfidence = append(result.LowConfidence, path)
+		}
+	}
+	sort.Strings(result.LowConfidence)
+}
+
+// score records the confidence of one value and returns where it was found
+// in the text, or -1. valid, if given, checks the value's shape.
+func (s *fieldScorer) score(path, value string, from int, valid func(string) bool) int {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return -1
+	}
+
+	confidence := s.base
+	src, exact := s.doc.Locate(value, from)
+	switch {
+	case src != nil && exact:
+		confidence += 0.15
+	case src != nil: // same letters and digits, other punctuation
+		confidence += 0.05
+	case len(value) > descriptionPrefix:
+		// Long text is often reflowed; find where it starts at least
+		prefix := value[:descriptionPrefix]
+		for !utf8.ValidString(prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+		if src, _ = s.doc.Locate(prefix, from); src != nil {
+			confidence -= 0.1
+		} else {
+			confidence *= 0.5
+		}
+	default: // inferred or rewritten, nothing to check it against
+		confidence *= 0.5
+	}
+	if valid != nil && !valid(value) {
+		confidence *= 0.5
+	}
+
+	s.fields[path] = model.FieldConfidence{Confidence: math.Round(math.Min(confidence, 1)*100) / 100, Source: src}
+	if src == nil {
+		return -1
+	}
+	return src.Start
+}
+
+// near returns where to look for the fields of an entry found at anchor:
+// the start of the line before, since a title or dates often sit above
+// the company. Without an anchor it is from.
+func (s *fieldScorer) near(anchor, from int) int {
+	if anchor < 0 {
+		return from
+	}
+	text := s.doc.Text
+	lineStart := strings.LastIndexByte(text[:anchor], '\n') + 1
+	if lineStart > 0 {
+		lineStart = strings.LastIndexByte(text[:lineStart-1], '\n') + 1
+	}
+	return max(lineStart, from)
+}
+
+func earliest(a, b int) int {
+	if a < 0 || (b >= 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+func validEmail(v string) bool {
+	return emailPattern.FindString(v) == v
+}
+
+func validPhone(v string) bool {
+	digits := 0
+	for _, r := range v {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return digits >= 7 && digits <= 15 && phonePattern.MatchString(v)
+}
+
+func matchesAll(pattern *regexp.Regexp) func(string) bool {
+	return func(v string) bool {
+		return pattern.FindString(v) == v
+	}
+}
+
+// validPeriod accepts years that normalized into a date range or a duration.
+func validPeriod(period *model.DateRange) func(string) bool {
+	return func(string) bool {
+		return period != nil && (period.Start != nil || period.DurationMonths > 0)
+	}
+}
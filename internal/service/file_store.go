@@ -0,0 +1,83 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+var ErrFileNotFound = errors.New("stored file not found")
+
+// FileStore keeps uploaded documents under slash-separated keys such as
+// "resumes/{userID}/{resumeID}". Other backends (S3, GCS) only need to
+// implement these three methods.
+type FileStore interface {
+	Put(key string, r io.Reader) (int64, error)
+	Open(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+// LocalFileStore stores files in a directory on disk.
+type LocalFileStore struct {
+	Dir string
+}
+
+func (s *LocalFileStore) path(key string) (string, error) {
+	rel := filepath.FromSlash(key)
+	if !filepath.IsLocal(rel) {
+		return "", errors.New("invalid storage key " + key)
+	}
+	return filepath.Join(s.Dir, rel), nil
+}
+
+// Put writes the file atomically: readers never see a partial upload.
+func (s *LocalFileStore) Put(key string, r io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(tmp, r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return 0, err
+	}
+	return n, nil
+}
+
+func (s *LocalFileStore) Open(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrFileNotFound
+	}
+	return f, err
+}
+
+func (s *LocalFileStore) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,474 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/utils"
+)
+
+// The heuristic parser reads a resume with patterns alone. It is the offline
+// fallback for when Gemini is unavailable: contact details come from
+// patterns, sections from their headings and entries are split on date ranges.
+
+// Month names, words joining two dates and words for "present", in the
+// languages the parser knows
+const (
+	monthNames   = `jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec|ene|abr|ago|dic|fev|set|out|mär|mai|okt|dez`
+	rangeWords   = `-|–|—|to|until|bis|hasta|até|a`
+	presentWords = `presente|present|current|now|today|date|actualidad|actualmente|actual|hoy|heute|aktuell|jetzt|atualmente|atual|o momento|वर्तमान`
+)
+
+var (
+	emailPattern     = regexp.MustCompile(`[\w.+-]+@[\w-]+(\.[\w-]+)+`)
+	phonePattern     = regexp.MustCompile(`\+?\(?\d[\d \t().-]{7,}\d`)
+	linkedInPattern  = regexp.MustCompile(`(?i)(https?://)?([\w-]+\.)?linkedin\.com/(in|pub)/[\w%-]+/?`)
+	gitHubPattern    = regexp.MustCompile(`(?i)(https?://)?(www\.)?github\.com/[\w-]+/?`)
+	urlPattern       = regexp.MustCompile(`(?i)(https?://|www\.)[^\s|,;()<>]+|\b[\w-]+(\.[\w-]+)*\.(dev|io|me|com|net|org|app|tech|xyz|site)(/[^\s|,;()<>]*)?`)
+	gpaPattern       = regexp.MustCompile(`(?i)\b(c?gpa|grade)\s*[:\-]?\s*(\d+(\.\d+)?(\s*/\s*\d+(\.\d+)?)?)`)
+	yearPattern      = regexp.MustCompile(`\b(19|20)\d{2}\b`)
+	bulletPattern    = regexp.MustCompile(`^\s*([-*•●▪◦‣–]|\d+[.)])\s+`)
+	skillSeparators  = regexp.MustCompile(`[,;|•·]`)
+	fieldSeparators  = regexp.MustCompile(`\s+[|·•–—]\s+|\s+-\s+|\s+at\s+|\t+`)
+	dateRangePattern = regexp.MustCompile(`(?i)((` + monthNames + `)\pL*\.?\s+(de\s+)?|\d{1,2}/)?(19|20)\d{2}\s*(` + rangeWords + `)\s*(((` + monthNames + `)\pL*\.?\s+(de\s+)?|\d{1,2}/)?(19|20)\d{2}|` + presentWords + `)`)
+	ongoingPattern   = regexp.MustCompile(`(?i)(` + presentWords + `)$`)
+
+	// Education usually shows a graduation year rather than a range
+	educationDatePattern = regexp.MustCompile(dateRangePattern.String() + `|` + yearPattern.String())
+)
+
+// Section headings by language, matched case-insensitively against a whole
+// line. English headings are recognized whatever the resume's language,
+// since resumes in other languages often keep them.
+var resumeSections = map[string]map[string]string{
+	utils.LangEnglish: {
+		"experience": "experience", "work experience": "experience", "professional experience": "experience",
+		"employment": "experience", "employment history": "experience", "work history": "experience",
+		"career history": "experience", "relevant experience": "experience",
+		"education": "education", "academic background": "education", "academics": "education",
+		"education and training": "education", "qualifications": "education",
+		"skills": "skills", "technical skills": "skills", "core competencies": "skills", "technologies": "skills",
+		"tech stack": "skills", "key skills": "skills", "skills and tools": "skills", "tools": "skills",
+		"summary": "other", "profile": "other", "about": "other", "about me": "other", "objective": "other",
+		"projects": "other", "certifications": "other", "awards": "other", "achievements": "other",
+		"publications": "other", "languages": "other", "interests": "other", "hobbies": "other",
+		"references": "other", "volunteering": "other", "contact": "other",
+	},
+	utils.LangSpanish: {
+		"experiencia": "experience", "experiencia laboral": "experience", "experiencia profesional": "experience",
+		"historial laboral": "experience", "trayectoria profesional": "experience",
+		"educación": "education", "formación": "education", "formación académica": "education", "estudios": "education",
+		"habilidades": "skills", "habilidades técnicas": "skills", "competencias": "skills", "conocimientos": "skills",
+		"conocimientos técnicos": "skills", "aptitudes": "skills", "tecnologías": "skills",
+		"perfil": "other", "perfil profesional": "other", "resumen": "other", "sobre mí": "other", "objetivo": "other",
+		"proyectos": "other", "certificaciones": "other", "premios": "other", "logros": "other",
+		"publicaciones": "other", "idiomas": "other", "intereses": "other", "aficiones": "other",
+		"referencias": "other", "voluntariado": "other", "contacto": "other", "datos personales": "other",
+	},
+	utils.LangGerman: {
+		"berufserfahrung": "experience", "berufliche erfahrung": "experience", "beruflicher werdegang": "experience",
+		"werdegang": "experience", "erfahrung": "experience", "praxiserfahrung": "experience",
+		"ausbildung": "education", "bildung": "education", "bildungsweg": "education", "studium": "education",
+		"akademischer werdegang": "education", "schulbildung": "education",
+		"kenntnisse": "skills", "fähigkeiten": "skills", "fachkenntnisse": "skills", "it-kenntnisse": "skills",
+		"kompetenzen": "skills", "technische kenntnisse": "skills", "technologien": "skills",
+		"profil": "other", "zusammenfassung": "other", "über mich": "other", "projekte": "other",
+		"zertifikate": "other", "zertifizierungen": "other", "auszeichnungen": "other", "publikationen": "other",
+		"sprachen": "other", "sprachkenntnisse": "other", "interessen": "other", "hobbys": "other",
+		"referenzen": "other", "ehrenamt": "other", "kontakt": "other", "persönliche daten": "other",
+	},
+	utils.LangPortuguese: {
+		"experiência": "experience", "experiência profissional": "experience", "experiência de trabalho": "experience",
+		"histórico profissional": "experience",
+		"educação":               "education", "formação": "education", "formação acadêmica": "education",
+		"formação académica": "education", "escolaridade": "education",
+		"habilidades": "skills", "habilidades técnicas": "skills", "competências": "skills",
+		"competências técnicas": "skills", "conhecimentos": "skills", "tecnologias": "skills",
+		"perfil": "other", "perfil profissional": "other", "resumo": "other", "sobre mim": "other", "objetivo": "other",
+		"projetos": "other", "certificações": "other", "prêmios": "other", "conquistas": "other",
+		"publicações": "other", "idiomas": "other", "interesses": "other", "referências": "other",
+		"voluntariado": "other", "contato": "other", "dados pessoais": "other",
+	},
+	utils.LangHindi: {
+		"अनुभव": "experience", "कार्य अनुभव": "experience", "पेशेवर अनुभव": "experience",
+		"शिक्षा": "education", "शैक्षणिक योग्यता": "education", "शैक्षिक योग्यता": "education",
+		"कौशल": "skills", "तकनीकी कौशल": "skills",
+		"सारांश": "other", "परिचय": "other", "उद्देश्य": "other", "परियोजनाएँ": "other", "परियोजनाएं": "other",
+		"प्रमाणपत्र": "other", "उपलब्धियाँ": "other", "भाषाएँ": "other", "भाषाएं": "other", "रुचियाँ": "other",
+		"संदर्भ": "other", "संपर्क": "other", "व्यक्तिगत विवरण": "other",
+	},
+}
+
+// Words that mark job titles, institutions and degrees, by language
+var (
+	jobTitleWords = map[string][]string{
+		utils.LangEnglish: {
+			"engineer", "developer", "manager", "intern", "lead", "analyst", "designer", "consultant",
+			"architect", "scientist", "director", "officer", "specialist", "administrator", "head",
+			"founder", "cto", "ceo", "programmer", "researcher", "associate", "assistant", "devops", "sre",
+		},
+		utils.LangSpanish: {
+			"ingeniero", "ingeniera", "desarrollador", "desarrolladora", "programador", "programadora", "gerente",
+			"jefe", "jefa", "director", "directora", "analista", "diseñador", "diseñadora", "consultor", "consultora",
+			"arquitecto", "arquitecta", "becario", "becaria", "responsable", "coordinador", "coordinadora", "técnico", "técnica",
+		},
+		utils.LangGerman: {
+			"entwickler", "entwicklerin", "softwareentwickler", "softwareentwicklerin", "ingenieur", "ingenieurin",
+			"leiter", "leiterin", "berater", "beraterin", "werkstudent", "werkstudentin", "praktikant", "praktikantin",
+			"architekt", "architektin", "geschäftsführer", "geschäftsführerin", "teamleiter", "teamleiterin", "referent", "referentin",
+		},
+		utils.LangPortuguese: {
+			"engenheiro", "engenheira", "desenvolvedor", "desenvolvedora", "programador", "programadora", "gerente",
+			"diretor", "diretora", "analista", "designer", "consultor", "consultora", "arquiteto", "arquiteta",
+			"estagiário", "estagiária", "coordenador", "coordenadora", "técnico", "técnica", "líder",
+		},
+		utils.LangHindi: {"इंजीनियर", "डेवलपर", "प्रबंधक", "विश्लेषक", "सलाहकार", "निदेशक", "प्रशिक्षु", "अधिकारी"},
+	}
+	institutionWords = map[string][]string{
+		utils.LangEnglish:    {"university", "college", "institute", "school", "academy", "polytechnic", "iit", "mit"},
+		utils.LangSpanish:    {"universidad", "instituto", "escuela", "colegio", "academia", "politécnica", "facultad"},
+		utils.LangGerman:     {"universität", "hochschule", "fachhochschule", "gymnasium", "akademie", "schule", "tu", "th", "lmu"},
+		utils.LangPortuguese: {"universidade", "faculdade", "instituto", "escola", "colégio", "academia", "usp", "unicamp"},
+		utils.LangHindi:      {"विश्वविद्यालय", "महाविद्यालय", "संस्थान", "विद्यालय"},
+	}
+	degreeWords = map[string][]string{
+		utils.LangEnglish: {
+			"bachelor", "master", "phd", "ph.d", "doctor", "mba", "diploma", "associate", "b.s", "bsc", "b.sc",
+			"b.tech", "btech", "b.e", "m.s", "msc", "m.sc", "m.tech", "mtech", "b.a", "m.a", "high school", "certificate",
+		},
+		utils.LangSpanish:    {"grado", "licenciatura", "licenciado", "licenciada", "máster", "doctorado", "ingeniería", "diplomatura", "bachillerato", "técnico superior"},
+		utils.LangGerman:     {"bachelor", "master", "diplom", "promotion", "abitur", "staatsexamen", "magister", "ausbildung"},
+		utils.LangPortuguese: {"bacharelado", "licenciatura", "mestrado", "doutorado", "doutoramento", "graduação", "pós-graduação", "tecnólogo", "mba"},
+		utils.LangHindi:      {"स्नातक", "स्नातकोत्तर", "डिप्लोमा", "पीएचडी"},
+	}
+)
+
+// vocabulary is what the heuristic parser recognizes in one resume: the
+// words of its language and English.
+type vocabulary struct {
+	sections                         map[string]string
+	jobTitles, institutions, degrees []string
+}
+
+func vocabularyFor(language string) *vocabulary {
+	languages := []string{utils.LangEnglish}
+	if language != utils.LangEnglish {
+		languages = append(languages, language)
+	}
+	v := &vocabulary{sections: map[string]string{}}
+	for _, lang := range languages {
+		for heading, section := range resumeSections[lang] {
+			v.sections[heading] = section
+		}
+		v.jobTitles = append(v.jobTitles, jobTitleWords[lang]...)
+		v.institutions = append(v.institutions, institutionWords[lang]...)
+		v.degrees = append(v.degrees, degreeWords[lang]...)
+	}
+	return v
+}
+
+// ParseResumeHeuristically builds a ParsedResume from resume text without an
+// LLM, recognizing headings and keywords in English and in language (an ISO
+// 639-1 code, "" if unknown).
+func ParseResumeHeuristically(text, language string) *model.ParsedResume {
+	v := vocabularyFor(language)
+	resume := &model.ParsedResume{
+		Skills:     model.StringList{},
+		Experience: []model.ParsedExperience{},
+		Education:  []model.ParsedEducation{},
+	}
+
+	resume.Email = emailPattern.FindString(text)
+	resume.LinkedIn = linkedInPattern.FindString(text)
+	resume.GitHub = gitHubPattern.FindString(text)
+	for _, u := range urlPattern.FindAllString(text, -1) {
+		lower := strings.ToLower(u)
+		if strings.Contains(lower, "linkedin.com") || strings.Contains(lower, "github.com") ||
+			strings.Contains(resume.Email, u) {
+			continue
+		}
+		resume.Portfolio = strings.TrimRight(u, "./")
+		break
+	}
+	for _, p := range phonePattern.FindAllString(text, -1) {
+		// Skip date ranges such as "2019 - 2021" that look like numbers
+		if digits := countDigits(p); digits >= 9 && digits <= 15 && !dateRangePattern.MatchString(p) {
+			resume.Phone = strings.TrimSpace(p)
+			break
+		}
+	}
+
+	sections := splitSections(text, v)
+	parseHeader(resume, sections["header"], v)
+	resume.Skills = parseSkills(sections["skills"])
+	resume.Experience = parseExperience(sections["experience"], v)
+	resume.Education = parseEducation(sections["education"], v)
+
+	for _, e := range resume.Experience {
+		if isOngoing(e.Years) {
+			resume.CurrentCompany = e.Company
+			break
+		}
+	}
+	if resume.Title == "" && len(resume.Experience) > 0 {
+		resume.Title = resume.Experience[0].Title
+	}
+	return resume
+}
+
+// splitSections groups resume lines under their section heading; lines
+// before the first heading belong to "header".
+func splitSections(text string, v *vocabulary) map[string][]string {
+	sections := map[string][]string{}
+	current := "header"
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if section, ok := sectionHeading(line, v); ok {
+			current = section
+			continue
+		}
+		sections[current] = append(sections[current], line)
+	}
+	return sections
+}
+
+func sectionHeading(line string, v *vocabulary) (string, bool) {
+	if utf8.RuneCountInString(line) > 40 {
+		return "", false
+	}
+	key := strings.ToLower(strings.Trim(line, " #:*_-=|"))
+	key = strings.Join(strings.Fields(strings.ReplaceAll(key, "&", "and")), " ")
+	section, ok := v.sections[key]
+	return section, ok
+}
+
+// parseHeader reads the name, headline and location from the top of the resume.
+func parseHeader(resume *model.ParsedResume, lines []string, v *vocabulary) {
+	for _, line := range lines {
+		for _, part := range splitParts(line) {
+			if containsContact(part) {
+				continue
+			}
+			switch {
+			case resume.FullName == "" && looksLikeName(part, v):
+				resume.FullName = part
+			case resume.FullName != "" && resume.Title == "" && !isLocation(part, v) && utf8.RuneCountInString(part) <= 80:
+				resume.Title = part
+			case resume.Location == "" && isLocation(part, v):
+				resume.Location = part
+			}
+		}
+	}
+}
+
+func parseSkills(lines []string) model.StringList {
+	skills := model.StringList{}
+	for _, line := range lines {
+		line = bulletPattern.ReplaceAllString(line, "")
+		// "Languages: Go, Python" lists skills after a label
+		if i := strings.Index(line, ":"); i >= 0 && i < 30 {
+			line = line[i+1:]
+		}
+		for _, s := range skillSeparators.Split(line, -1) {
+			s = strings.TrimSpace(s)
+			if s != "" && utf8.RuneCountInString(s) <= 50 && !containsFold([]string(skills), s) {
+				skills = append(skills, s)
+			}
+		}
+	}
+	return skills
+}
+
+// resumeEntry is one dated block of a section: its header lines and the
+// lines that follow them.
+type resumeEntry struct {
+	header []string
+	years  string
+	body   []string
+}
+
+// splitEntries splits section lines into entries at each date range. Up to
+// two plain lines just above a date line belong to that entry's header.
+func splitEntries(lines []string, datePattern *regexp.Regexp) []resumeEntry {
+	var entries []resumeEntry
+	start := 0 // first line not yet assigned to an entry header
+	for i, line := range lines {
+		date := datePattern.FindString(line)
+		if date == "" || bulletPattern.MatchString(line) {
+			continue
+		}
+
+		from := i
+		for from > start && i-from < 2 && !bulletPattern.MatchString(lines[from-1]) && utf8.RuneCountInString(lines[from-1]) <= 100 {
+			from--
+		}
+		if len(entries) > 0 {
+			prev := &entries[len(entries)-1]
+			prev.body = append(prev.body, lines[start:from]...)
+		}
+
+		header := append([]string{}, lines[from:i]...)
+		if rest := strings.Trim(strings.Replace(line, date, "", 1), " |,-–—()"); rest != "" {
+			header = append(header, rest)
+		}
+		entries = append(entries, resumeEntry{header: header, years: strings.TrimSpace(date)})
+		start = i + 1
+	}
+	if len(entries) > 0 {
+		prev := &entries[len(entries)-1]
+		prev.body = append(prev.body, lines[start:]...)
+	}
+	return entries
+}
+
+func parseExperience(lines []string, v *vocabulary) []model.ParsedExperience {
+	out := []model.ParsedExperience{}
+	for _, e := range splitEntries(lines, dateRangePattern) {
+		exp := model.ParsedExperience{Years: e.years}
+		var others []string
+		for _, line := range e.header {
+			for _, part := range splitParts(line) {
+				switch {
+				case exp.Title == "" && hasWord(part, v.jobTitles):
+					exp.Title = part
+				case exp.Location == "" && isLocation(part, v):
+					exp.Location = part
+				default:
+					others = append(others, part)
+				}
+			}
+		}
+		if len(others) > 0 {
+			exp.Company = others[0]
+		}
+		if exp.Title == "" && len(others) > 1 {
+			exp.Title = others[1]
+		}
+
+		var desc []string
+		for _, line := range e.body {
+			desc = append(desc, bulletPattern.ReplaceAllString(line, "- "))
+		}
+		exp.Description = strings.Join(desc, "\n")
+		out = append(out, exp)
+	}
+	return out
+}
+
+func parseEducation(lines []string, v *vocabulary) []model.ParsedEducation {
+	out := []model.ParsedEducation{}
+	for _, e := range splitEntries(lines, educationDatePattern) {
+		edu := model.ParsedEducation{Years: e.years}
+		var others []string
+		for _, line := range append(e.header, e.body...) {
+			if m := gpaPattern.FindStringSubmatch(line); m != nil && edu.GPA == "" {
+				edu.GPA = strings.ReplaceAll(m[2], " ", "")
+				line = strings.Replace(line, m[0], "", 1)
+			}
+			for _, part := range splitParts(line) {
+				switch {
+				case edu.Institution == "" && hasWord(part, v.institutions):
+					edu.Institution = part
+				case edu.Degree == "" && hasWord(part, v.degrees):
+					edu.Degree = part
+				case edu.Location == "" && isLocation(part, v):
+					edu.Location = part
+				default:
+					others = append(others, part)
+				}
+			}
+		}
+		if edu.Institution == "" && len(others) > 0 {
+			edu.Institution = others[0]
+		}
+		if edu.Institution != "" {
+			out = append(out, edu)
+		}
+	}
+	return out
+}
+
+// splitParts splits a line on the separators resumes use between fields.
+func splitParts(line string) []string {
+	line = bulletPattern.ReplaceAllString(line, "")
+	var parts []string
+	for _, p := range fieldSeparators.Split(line, -1) {
+		if p = strings.Trim(p, " ,;"); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+func containsContact(s string) bool {
+	return emailPattern.MatchString(s) || urlPattern.MatchString(s) ||
+		(phonePattern.MatchString(s) && countDigits(s) >= 9)
+}
+
+// looksLikeName accepts two to five capitalized words of letters; words in
+// scripts without case, such as Devanagari, only need to be letters.
+func looksLikeName(s string, v *vocabulary) bool {
+	words := strings.Fields(s)
+	if len(words) < 2 || len(words) > 5 {
+		return false
+	}
+	for _, w := range words {
+		r := []rune(w)
+		if !unicode.IsLetter(r[0]) || unicode.IsLower(r[0]) {
+			return false
+		}
+		for _, c := range r {
+			if !unicode.IsLetter(c) && !unicode.IsMark(c) && c != '.' && c != '-' && c != '\'' {
+				return false
+			}
+		}
+	}
+	return !hasWord(s, v.jobTitles)
+}
+
+func isLocation(s string, v *vocabulary) bool {
+	if len(strings.Fields(s)) > 5 || hasWord(s, v.institutions) {
+		return false
+	}
+	loc := utils.ResolveLocation(s)
+	return loc.Precision == model.PrecisionCity ||
+		(loc.Precision != "" && strings.Contains(s, ",")) ||
+		(loc.Precision == "" && loc.WorkMode == model.WorkModeRemote && len(strings.Fields(s)) <= 2)
+}
+
+// hasWord reports whether s contains one of words as a whole word.
+func hasWord(s string, words []string) bool {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsMark(r) && !unicode.IsDigit(r) && r != '.'
+	})
+	for i, f := range fields {
+		fields[i] = strings.Trim(f, ".") // "M.Sc." ends a sentence-like abbreviation
+	}
+	lower := " " + strings.Join(fields, " ") + " "
+	for _, w := range words {
+		if strings.Contains(lower, " "+w+" ") || strings.Contains(lower, " "+w+"s ") {
+			return true
+		}
+	}
+	return false
+}
+
+func isOngoing(years string) bool {
+	return ongoingPattern.MatchString(strings.TrimSpace(years))
+}
+
+func countDigits(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/utils"
+)
+
+var ErrInvalidJobOutput = errors.New("parser output does not match the job schema")
+
+//go:embed schema/parsed_job.v1.json
+var ParsedJobSchema []byte
+
+var parsedJobSchema map[string]interface{}
+
+func init() {
+	if err := json.Unmarshal(ParsedJobSchema, &parsedJobSchema); err != nil {
+		panic("invalid parsed job schema: " + err.Error())
+	}
+}
+
+// maxJobTags is how many skills the heuristic parser tags a job with
+const maxJobTags = 20
+
+// ParseJobDescription extracts a job description's text and parses it into
+// a job as opts say. Job descriptions are company documents, so they are
+// sent to Gemini without PII redaction.
+func ParseJobDescription(filePath string, opts ParseOptions) (*model.JobParseResult, error) {
+	mode := opts.Mode
+	if err := ValidateParserMode(mode); err != nil {
+		return nil, err
+	}
+
+	doc, err := utils.ExtractDocument(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if mode == ParserModeHeuristic {
+		return parseJobHeuristically(doc, ""), nil
+	}
+
+	result, err := parseJobWithGemini(doc)
+	if err != nil && mode == ParserModeAuto {
+		log.Printf("⚠️ Gemini job description parsing failed, using heuristics: %v", err)
+		return parseJobHeuristically(doc, err.Error()), nil
+	}
+	return result, err
+}
+
+func parseJobWithGemini(doc *utils.Document) (*model.JobParseResult, error) {
+	text := utils.SanitizeText(doc.Text)
+	raw, err := utils.CallGeminiText(jobPrompt(text))
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode strictly; send invalid output back with the problems found
+	job, problems := decodeParsedJob(raw)
+	repairs := 0
+	for len(problems) > 0 && repairs < maxRepairAttempts {
+		repairs++
+		raw, err = utils.CallGeminiText(repairPrompt(ParsedJobSchema, text, raw, problems))
+		if err != nil {
+			return nil, err
+		}
+		job, problems = decodeParsedJob(raw)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidJobOutput, strings.Join(problems, "; "))
+	}
+	return &model.JobParseResult{Format: doc.Format, Parser: parserGemini, Repairs: repairs, Parsed: job}, nil
+}
+
+// decodeParsedJob validates an LLM reply against the job schema and decodes
+// it, or returns the problems found.
+func decodeParsedJob(raw string) (*model.ParsedJob, []string) {
+	jsonStr := utils.ExtractJSONObject(raw)
+
+	var doc interface{}
+	if err := json.Unmarshal([]byte(jsonStr), &doc); err != nil {
+		return nil, []string{"not valid JSON: " + err.Error()}
+	}
+	if problems := utils.ValidateJSONSchema(parsedJobSchema, doc); len(problems) > 0 {
+		return nil, problems
+	}
+
+	dec := json.NewDecoder(strings.NewReader(jsonStr))
+	dec.DisallowUnknownFields()
+	var job model.ParsedJob
+	if err := dec.Decode(&job); err != nil {
+		return nil, []string{err.Error()}
+	}
+	return &job, nil
+}
+
+func jobPrompt(text string) string {
+	return fmt.Sprintf(`You are a job description parsing assistant. Extract the job posting below into a single JSON object that conforms to this JSON Schema. Return only the JSON, with no Markdown fence or commentary. Use "" for missing text fields, 0 for a missing salary and [] for missing lists. Convert salaries written as "120k" or "12 LPA" into plain amounts (120000, 1200000).
+
+Schema:
+%s
+
+Job description:
+"%s"`, ParsedJobSchema, text)
+}
+
+// The heuristic job parser reads labeled lines ("Location: Berlin"), the
+// title line, salary and employment type patterns, and tags the job with the
+// skills of the taxonomy that the text mentions.
+
+var (
+	jobLabelPattern = regexp.MustCompile(`(?i)^(job title|position|role|title|company|employer|organi[sz]ation|location|based in|salary|compensation|pay|employment type|job type|contract type|type)\s*[:：]\s*(.+)$`)
+	aboutPattern    = regexp.MustCompile(`(?i)^about\s+(.+?):?$`)
+	titleSeparators = regexp.MustCompile(`\s+(at|@|[|–—-])\s+`)
+
+	// "$120,000 - $150,000 per year", "€60k–80k", "INR 12-18 LPA", "USD 45/hour"
+	salaryPattern = regexp.MustCompile(`(?i)([$€£₹]|\b(?-i:[A-Z]{3,4})\b|\brs\.?)\s*(\d[\d,.]*)\s*(k|lpa|lakhs?|m)?\s*(?:-|–|—|to)\s*(?:[$€£₹]|(?-i:[A-Z]{3,4})\s*)?(\d[\d,.]*)\s*(k|lpa|lakhs?|m)?\b(?:\s*(?-i:[A-Z]{3,4})\b)?\s*(?:/|per|an|a)?\s*(hour|hr|day|week|month|mo|year|yr|annum|annually)?`)
+
+	jobTypePatterns = []struct {
+		pattern *regexp.Regexp
+		jobType string
+	}{
+		{regexp.MustCompile(`(?i)\bintern(ship)?\b`), "Internship"},
+		{regexp.MustCompile(`(?i)\bpart[- ]time\b`), "Part-time"},
+		{regexp.MustCompile(`(?i)\b(contract(or)?|freelance)\b`), "Contract"},
+		{regexp.MustCompile(`(?i)\b(temporary|fixed[- ]term)\b`), "Temporary"},
+		{regexp.MustCompile(`(?i)\bfull[- ]time\b`), "Full-time"},
+	}
+)
+
+// salarySymbols maps currency symbols to their codes; "$" is taken as USD
+var salarySymbols = map[string]string{"$": "USD", "€": "EUR", "£": "GBP", "₹": "INR", "rs": "INR", "rs.": "INR"}
+
+// salaryPeriods maps the words a salary period is written with to Job.SalaryPeriod
+var salaryPeriods = map[string]string{
+	"hour": model.PayPeriodHour, "hr": model.PayPeriodHour, "day": model.PayPeriodDay, "week": model.PayPeriodWeek,
+	"month": model.PayPeriodMonth, "mo": model.PayPeriodMonth,
+	"year": model.PayPeriodYear, "yr": model.PayPeriodYear, "annum": model.PayPeriodYear, "annually": model.PayPeriodYear,
+}
+
+// parseJobHeuristically runs the offline job description parser. Its
+// output is checked against the same schema; problems are warnings.
+func parseJobHeuristically(doc *utils.Document, fallbackReason string) *model.JobParseResult {
+	job := ParseJobHeuristically(doc.Text)
+
+	var warnings []string
+	if data, err := json.Marshal(job); err == nil {
+		var v interface{}
+		json.Unmarshal(data, &v)
+		warnings = utils.ValidateJSONSchema(parsedJobSchema, v)
+	}
+	return &model.JobParseResult{
+		Format:         doc.Format,
+		Parser:         parserHeuristic,
+		FallbackReason: fallbackReason,
+		Warnings:       warnings,
+		Parsed:         job,
+	}
+}
+
+// ParseJobHeuristically parses job description text with patterns alone.
+func ParseJobHeuristically(text string) *model.ParsedJob {
+	job := &model.ParsedJob{Tags: model.StringList{}}
+	var body []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if m := jobLabelPattern.FindStringSubmatch(line); m != nil {
+			value := strings.TrimSpace(m[2])
+			switch label := strings.ToLower(m[1]); {
+			case strings.Contains(label, "title") || label == "position" || label == "role":
+				setOnce(&job.Title, value)
+			case label == "company" || label == "employer" || strings.HasPrefix(label, "organi"):
+				setOnce(&job.Company, value)
+			case label == "location" || label == "based in":
+				setOnce(&job.Location, value)
+			case label == "salary" || label == "compensation" || label == "pay":
+				parseSalary(job, value)
+			default:
+				setOnce(&job.Type, jobType(value))
+			}
+			continue
+		}
+		if m := aboutPattern.FindStringSubmatch(line); m != nil && job.Company == "" && !isAboutTheRole(m[1]) {
+			job.Company = m[1]
+		}
+		if job.Title == "" && line != "" && utf8.RuneCountInString(line) <= 100 && len(body) == 0 {
+			// The first line is the title, possibly followed by the company and location
+			parts := titleSeparators.Split(line, -1)
+			job.Title = parts[0]
+			if len(parts) > 1 {
+				setOnce(&job.Company, parts[1])
+			}
+			if len(parts) > 2 {
+				setOnce(&job.Location, parts[2])
+			}
+			continue
+		}
+		body = append(body, line)
+	}
+	job.Description = strings.TrimSpace(strings.Join(body, "\n"))
+
+	if job.SalaryMax == 0 {
+		parseSalary(job, text)
+	}
+	setOnce(&job.Type, jobType(text))
+	if job.Location == "" {
+		switch utils.DetectWorkMode(text) {
+		case model.WorkModeRemote:
+			job.Location = "Remote"
+		case model.WorkModeHybrid:
+			job.Location = "Hybrid"
+		}
+	}
+	tags := skillTaxonomy.Find(text)
+	job.Tags = tags[:min(len(tags), maxJobTags)]
+	return job
+}
+
+func setOnce(field *string, value string) {
+	if *field == "" {
+		*field = strings.TrimSpace(value)
+	}
+}
+
+// isAboutTheRole tells "About the role" and "About you" from "About Acme".
+func isAboutTheRole(s string) bool {
+	switch strings.ToLower(strings.Fields(s)[0]) {
+	case "the", "this", "you", "us", "our", "your", "role", "position", "job":
+		return true
+	}
+	return false
+}
+
+// jobType returns the employment type text names first, or "".
+func jobType(text string) string {
+	first, best := "", -1
+	for _, t := range jobTypePatterns {
+		if loc := t.pattern.FindStringIndex(text); loc != nil && (best < 0 || loc[0] < best) {
+			first, best = t.jobType, loc[0]
+		}
+	}
+	return first
+}
+
+// parseSalary fills in the salary range of the first "min - max" amount in
+// text, with its currency and period.
+func parseSalary(job *model.ParsedJob, text string) {
+	m := salaryPattern.FindStringSubmatch(text)
+	if m == nil {
+		return
+	}
+	currency := strings.ToUpper(strings.TrimSpace(m[1]))
+	if code, ok := salarySymbols[strings.ToLower(strings.TrimSpace(m[1]))]; ok {
+		currency = code
+	}
+	lo, hi := salaryAmount(m[2], m[3]), salaryAmount(m[4], m[5])
+	if m[3] == "" && m[5] != "" {
+		lo = salaryAmount(m[2], m[5]) // "60-80k"
+	}
+	if lo <= 0 || hi < lo {
+		return
+	}
+	job.SalaryMin, job.SalaryMax, job.SalaryCurrency = lo, hi, currency
+	job.SalaryPeriod = model.PayPeriodYear
+	if p, ok := salaryPeriods[strings.ToLower(m[6])]; ok {
+		job.SalaryPeriod = p
+	}
+}
+
+// salaryAmount reads "120,000", "120k" or "12" with "LPA" (lakh per annum).
+func salaryAmount(number, unit string) float64 {
+	v, err := strconv.ParseFloat(strings.ReplaceAll(number, ",", ""), 64)
+	if err != nil {
+		return 0
+	}
+	switch strings.ToLower(unit) {
+	case "k":
+		v *= 1e3
+	case "m":
+		v *= 1e6
+	case "lpa", "lakh", "lakhs":
+		v *= 1e5
+	}
+	return math.Round(v*100) / 100
+}
@@ -1,8 +1,15 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/utils"
 	"gorm.io/gorm"
 )
 
@@ -10,13 +17,93 @@ type JobService struct {
 	DB *gorm.DB
 }
 
+var ErrJobAlreadyPublished = errors.New("job is already published")
+
 func (s *JobService) CreateJob(job *model.Job) error {
 	return s.DB.Create(job).Error
 }
 
+// PrepareJob normalizes a job before it is saved: its location, its tags
+// onto the skill taxonomy, its salary and its status, which defaults to
+// published. Errors describe what is invalid.
+func PrepareJob(job *model.Job, rates map[string]model.ExchangeRate) error {
+	job.Geo = utils.ResolveLocationWithMode(job.Location, job.Geo)
+	job.Tags = NormalizeSkills(job.Tags)
+	switch job.Status {
+	case "":
+		job.Status = model.JobStatusPublished
+	case model.JobStatusDraft, model.JobStatusPublished:
+	default:
+		return fmt.Errorf("Invalid status. Must be draft or published")
+	}
+	return ValidateSalary(job, rates)
+}
+
+// GetRecruiter loads a user with the recruiter role, or ErrRecruiterNotFound.
+func (s *JobService) GetRecruiter(id uuid.UUID) (*model.User, error) {
+	var recruiter model.User
+	if err := s.DB.Where("id = ? AND role = ?", id, "recruiter").First(&recruiter).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRecruiterNotFound
+		}
+		return nil, err
+	}
+	return &recruiter, nil
+}
+
+// CreateDraft saves a parsed job description as a draft job of the
+// recruiter and sets it on the result. A salary that cannot be kept, such as
+// one in an unknown currency, is dropped with a warning for the recruiter to
+// fill in.
+func (s *JobService) CreateDraft(recruiterID uuid.UUID, result *model.JobParseResult, rates map[string]model.ExchangeRate) error {
+	p := result.Parsed
+	job := &model.Job{
+		Title:          p.Title,
+		Company:        p.Company,
+		Location:       p.Location,
+		SalaryMin:      p.SalaryMin,
+		SalaryMax:      p.SalaryMax,
+		SalaryCurrency: p.SalaryCurrency,
+		SalaryPeriod:   p.SalaryPeriod,
+		Type:           p.Type,
+		Description:    p.Description,
+		Tags:           strings.Join(p.Tags, ", "),
+		Status:         model.JobStatusDraft,
+		CreatedAt:      time.Now(),
+		RecruiterID:    recruiterID,
+	}
+	if job.SalaryMax == 0 {
+		job.SalaryMax = job.SalaryMin
+	}
+	if err := PrepareJob(job, rates); err != nil {
+		result.Warnings = append(result.Warnings, "salary dropped: "+err.Error())
+		job.SalaryMin, job.SalaryMax, job.SalaryCurrency, job.SalaryPeriod = 0, 0, "", ""
+		if err := PrepareJob(job, rates); err != nil {
+			return err
+		}
+	}
+
+	if err := s.CreateJob(job); err != nil {
+		return err
+	}
+	result.Job = job
+	return nil
+}
+
+// PublishJob saves a reviewed draft as published, so that it is listed and
+// open to applications. The caller prepares the job first.
+func (s *JobService) PublishJob(job *model.Job) error {
+	if job.Status == model.JobStatusPublished {
+		return ErrJobAlreadyPublished
+	}
+	job.Status = model.JobStatusPublished
+	return s.DB.Omit("Recruiter").Save(job).Error
+}
+
+// GetJobs lists published jobs, newest first.
 func (s *JobService) GetJobs() ([]model.Job, error) {
 	var jobs []model.Job
-	err := s.DB.Order("created_at desc").Find(&jobs).Error
+	err := s.DB.Where("status = ?", model.JobStatusPublished).Order("created_at desc").Find(&jobs).Error
 	return jobs, err
 }
 
@@ -60,7 +147,7 @@ func (js *JobService) GetUserByID(userID string) (*model.User, error) {
 
 func (js *JobService) GetAllJobs() ([]model.Job, error) {
 	var jobs []model.Job
-	if err := js.DB.Find(&jobs).Error; err != nil {
+	if err := js.DB.Where("status = ?", model.JobStatusPublished).Find(&jobs).Error; err != nil {
 		return nil, err
 	}
 	return jobs, nil
@@ -69,3 +156,151 @@ func (js *JobService) GetAllJobs() ([]model.Job, error) {
 func (js *JobService) UpdateUserCredits(userID uuid.UUID, credits int) error {
 	return js.DB.Model(&model.User{}).Where("id = ?", userID).Update("credits", credits).Error
 }
+
+// JobFilter narrows job listings. Zero values mean "no filter".
+type JobFilter struct {
+	Near          *model.Location // centre of a radius search
+	RadiusKm      float64
+	IncludeRemote bool // keep remote jobs in a radius search
+	RemoteOnly    bool
+	WorkMode      string
+
+	// Salary filters are annual amounts in Currency. Setting Currency also
+	// fills SalaryNormalized on the returned jobs.
+	Currency  string
+	SalaryMin float64
+	SalaryMax float64
+}
+
+// ErrUnknownCurrency is returned when a filter asks for a currency without an exchange rate.
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// SearchJobs lists published jobs matching the filter, newest first. Radius searches
+// prefilter on a bounding box in SQL and then check the exact distance.
+func (s *JobService) SearchJobs(f JobFilter) ([]model.Job, error) {
+	query := s.DB.Where("status = ?", model.JobStatusPublished).Order("created_at desc")
+
+	if f.RemoteOnly {
+		query = query.Where("geo_work_mode = ?", model.WorkModeRemote)
+	} else if f.WorkMode != "" {
+		query = query.Where("geo_work_mode = ?", f.WorkMode)
+	}
+
+	if f.Near != nil && f.RadiusKm > 0 {
+		latDelta := f.RadiusKm / 111.0
+		lonDelta := 180.0
+		if cos := math.Cos(f.Near.Latitude * math.Pi / 180); cos > 0.01 {
+			lonDelta = math.Min(180, f.RadiusKm/(111.0*cos))
+		}
+
+		box := s.DB.Where("geo_precision = ? AND geo_latitude BETWEEN ? AND ?",
+			model.PrecisionCity, f.Near.Latitude-latDelta, f.Near.Latitude+latDelta)
+		if lonDelta < 180 && math.Abs(f.Near.Longitude)+lonDelta <= 180 {
+			box = box.Where("geo_longitude BETWEEN ? AND ?", f.Near.Longitude-lonDelta, f.Near.Longitude+lonDelta)
+		}
+		if f.IncludeRemote {
+			query = query.Where(box.Or("geo_work_mode = ?", model.WorkModeRemote))
+		} else {
+			query = query.Where(box)
+		}
+	}
+
+	var jobs []model.Job
+	if err := query.Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	if f.Near != nil && f.RadiusKm > 0 {
+		jobs = filterByDistance(jobs, f)
+	}
+
+	if f.Currency != "" {
+		rates, err := loadExchangeRates(s.DB)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := rates[f.Currency]; !ok {
+			return nil, ErrUnknownCurrency
+		}
+		jobs = filterBySalary(jobs, f, rates)
+	}
+
+	return jobs, nil
+}
+
+func filterByDistance(jobs []model.Job, f JobFilter) []model.Job {
+	filtered := jobs[:0]
+	for _, job := range jobs {
+		if f.IncludeRemote && job.Geo.WorkMode == model.WorkModeRemote {
+			filtered = append(filtered, job)
+			continue
+		}
+		if job.Geo.HasCoordinates() &&
+			utils.HaversineKm(f.Near.Latitude, f.Near.Longitude, job.Geo.Latitude, job.Geo.Longitude) <= f.RadiusKm {
+			filtered = append(filtered, job)
+		}
+	}
+	return filtered
+}
+
+// filterBySalary keeps jobs whose normalized range overlaps [SalaryMin, SalaryMax].
+func filterBySalary(jobs []model.Job, f JobFilter, rates map[string]model.ExchangeRate) []model.Job {
+	salaryFilter := f.SalaryMin > 0 || f.SalaryMax > 0
+
+	filtered := jobs[:0]
+	for _, job := range jobs {
+		normalized, ok := NormalizeSalary(job, f.Currency, rates)
+		if !ok {
+			if !salaryFilter {
+				filtered = append(filtered, job)
+			}
+			continue
+		}
+		if f.SalaryMin > 0 && normalized.Max < f.SalaryMin {
+			continue
+		}
+		if f.SalaryMax > 0 && normalized.Min > f.SalaryMax {
+			continue
+		}
+		job.SalaryNormalized = normalized
+		filtered = append(filtered, job)
+	}
+	return filtered
+}
+
+// BackfillLocations resolves the structured location of jobs and users that
+// were saved before locations were normalized.
+func (s *JobService) BackfillLocations() error {
+	var jobs []model.Job
+	if err := s.DB.Where("location <> '' AND (geo_precision IS NULL OR geo_precision = '') AND (geo_work_mode IS NULL OR geo_work_mode = '')").Find(&jobs).Error; err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if err := s.DB.Model(&model.Job{}).Where("id = ?", job.ID).Updates(geoColumns(utils.ResolveLocation(job.Location))).Error; err != nil {
+			return err
+		}
+	}
+
+	var users []model.User
+	if err := s.DB.Where("location <> '' AND (geo_precision IS NULL OR geo_precision = '') AND (geo_work_mode IS NULL OR geo_work_mode = '')").Find(&users).Error; err != nil {
+		return err
+	}
+	for _, user := range users {
+		if err := s.DB.Model(&model.User{}).Where("id = ?", user.ID).Updates(geoColumns(utils.ResolveLocation(user.Location))).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func geoColumns(geo model.Location) map[string]interface{} {
+	return map[string]interface{}{
+		"geo_city":      geo.City,
+		"geo_region":    geo.Region,
+		"geo_country":   geo.Country,
+		"geo_latitude":  geo.Latitude,
+		"geo_longitude": geo.Longitude,
+		"geo_precision": geo.Precision,
+		"geo_work_mode": geo.WorkMode,
+	}
+}
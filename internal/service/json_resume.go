@@ -0,0 +1,490 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/utils"
+	"gorm.io/gorm"
+)
+
+// JSONResumeSchema is the JSON Resume version profiles are exported as
+const JSONResumeSchema = "https://raw.githubusercontent.com/jsonresume/resume-schema/v1.0.0/schema.json"
+
+var ErrInvalidJSONResume = errors.New("invalid JSON Resume")
+
+// JSON Resume (jsonresume.org) documents are mapped onto the profile as
+// follows; every other field is kept in an extension blob, on the user or on
+// the work or education entry it belongs to, and written back on export.
+//
+//	basics.name, label, image, phone, url  full name, title, image, phone, portfolio
+//	basics.location                        location
+//	basics.profiles (LinkedIn, GitHub)     linkedin, github
+//	work[]                                 experience (name, position, location, summary, dates)
+//	education[]                            education (institution, studyType and area, score, dates)
+//	skills[] (keywords, or the name alone)   skills
+//
+// basics.email is not imported: it is the login.
+
+// ImportJSONResume saves a JSON Resume onto the profile. Basics that are set
+// replace the profile's; work, education and skills replace the profile's
+// when the document has them.
+func (s *ProfileService) ImportJSONResume(userID string, doc map[string]interface{}) (*model.User, error) {
+	doc = copyJSONObject(doc)
+	delete(doc, "$schema")
+
+	basics, err := takeJSONObject(doc, "basics")
+	if err != nil {
+		return nil, err
+	}
+	work, hasWork, err := takeJSONObjects(doc, "work")
+	if err != nil {
+		return nil, err
+	}
+	education, hasEducation, err := takeJSONObjects(doc, "education")
+	if err != nil {
+		return nil, err
+	}
+	skills, hasSkills, err := jsonObjects(doc, "skills") // kept whole, for their grouping
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	for _, f := range []struct{ key, column string }{
+		{"name", "full_name"}, {"label", "title"}, {"image", "image"}, {"phone", "phone"}, {"url", "portfolio"},
+	} {
+		if v := takeJSONString(basics, f.key); v != "" {
+			updates[f.column] = v
+		}
+	}
+	if loc, _ := basics["location"].(map[string]interface{}); loc != nil {
+		if location := jsonResumeLocation(loc); location != "" {
+			updates["location"] = location
+			for k, v := range geoColumns(utils.ResolveLocation(location)) {
+				updates[k] = v
+			}
+		}
+	}
+	if profiles, ok := basics["profiles"].([]interface{}); ok {
+		var others []interface{}
+		for _, p := range profiles {
+			profile, _ := p.(map[string]interface{})
+			url := jsonString(profile, "url")
+			switch network := strings.ToLower(jsonString(profile, "network")); {
+			case network == "linkedin" && url != "":
+				updates["linked_in"] = url
+			case network == "github" && url != "":
+				updates["git_hub"] = url
+			default:
+				others = append(others, p)
+			}
+		}
+		if len(others) > 0 {
+			basics["profiles"] = others
+		} else {
+			delete(basics, "profiles")
+		}
+	}
+	if len(basics) > 0 {
+		doc["basics"] = basics
+	}
+
+	var experience []model.Experience
+	for _, w := range work {
+		company := takeJSONString(w, "name")
+		if company == "" {
+			company = takeJSONString(w, "company") // JSON Resume before v1
+		}
+		e := model.Experience{
+			Company:     company,
+			Title:       takeJSONString(w, "position"),
+			Location:    takeJSONString(w, "location"),
+			Description: takeJSONString(w, "summary"),
+			Years:       jsonResumeYears(w),
+			Extensions:  w,
+		}
+		e.Period = utils.ParseDateRange(e.Years)
+		if e.Company == "" && e.Title == "" {
+			continue
+		}
+		if _, current := updates["current_company"]; !current && e.Company != "" && e.Period.Ongoing {
+			updates["current_company"] = e.Company
+		}
+		experience = append(experience, e)
+	}
+
+	var schooling []model.Education
+	for _, ed := range education {
+		e := model.Education{
+			Institution: takeJSONString(ed, "institution"),
+			Location:    takeJSONString(ed, "location"),
+			Degree:      jsonResumeDegree(ed),
+			GPA:         takeJSONString(ed, "score"),
+			Years:       jsonResumeYears(ed),
+			Extensions:  ed,
+		}
+		e.Period = utils.ParseDateRange(e.Years)
+		if e.Institution == "" {
+			continue
+		}
+		schooling = append(schooling, e)
+	}
+
+	if hasSkills {
+		var names []string
+		for _, skill := range skills {
+			// With keywords the name labels a group, e.g. "Web Development"
+			keywords, _ := skill["keywords"].([]interface{})
+			if len(keywords) == 0 {
+				names = append(names, jsonString(skill, "name"))
+			}
+			for _, k := range keywords {
+				if k, ok := k.(string); ok {
+					names = append(names, k)
+				}
+			}
+		}
+		// Unknown skills are reported outside the transaction
+		updates["skills"] = strings.Join(skillTaxonomy.Normalize(names), ", ")
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		if err := tx.Select("id").First(&user, "id = ?", userID).Error; err != nil {
+			return err
+		}
+		// A map update skips the JSON serializer, so the blob goes through the struct
+		user.ResumeExtensions = doc
+		if err := tx.Model(&user).Select("resume_extensions").Updates(&user).Error; err != nil {
+			return fmt.Errorf("update profile: %w", err)
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&user).Updates(updates).Error; err != nil {
+				return fmt.Errorf("update profile: %w", err)
+			}
+		}
+
+		if hasEducation {
+			if err := tx.Where("user_id = ?", user.ID).Delete(&model.Education{}).Error; err != nil {
+				return fmt.Errorf("clear education: %w", err)
+			}
+			for i := range schooling {
+				schooling[i].UserID = user.ID
+				if err := tx.Create(&schooling[i]).Error; err != nil {
+					return fmt.Errorf("add education: %w", err)
+				}
+			}
+		}
+		if hasWork {
+			if err := tx.Where("user_id = ?", user.ID).Delete(&model.Experience{}).Error; err != nil {
+				return fmt.Errorf("clear experience: %w", err)
+			}
+			for i := range experience {
+				experience[i].UserID = user.ID
+				if err := tx.Create(&experience[i]).Error; err != nil {
+					return fmt.Errorf("add experience: %w", err)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetUser(userID)
+}
+
+// ExportJSONResume writes the profile as a JSON Resume, with the fields kept
+// from the last import put back.
+func ExportJSONResume(user *model.User) map[string]interface{} {
+	doc := copyJSONObject(user.ResumeExtensions)
+	doc["$schema"] = JSONResumeSchema
+
+	basics, _ := doc["basics"].(map[string]interface{})
+	if basics == nil {
+		basics = map[string]interface{}{}
+	}
+	for key, v := range map[string]string{
+		"name": user.FullName, "label": user.Title, "image": user.Image, "email": user.Email,
+		"phone": user.Phone, "url": user.Portfolio,
+	} {
+		if v != "" {
+			basics[key] = v
+		}
+	}
+	// The imported location is kept, postcode and all, while the profile's is unchanged
+	if loc, _ := basics["location"].(map[string]interface{}); loc == nil || jsonResumeLocation(loc) != user.Location {
+		delete(basics, "location")
+		if user.Geo.Precision != "" {
+			basics["location"] = dropEmpty(map[string]interface{}{
+				"city": user.Geo.City, "region": user.Geo.Region, "countryCode": user.Geo.Country,
+			})
+		} else if user.Location != "" {
+			basics["location"] = map[string]interface{}{"city": user.Location}
+		}
+	}
+	profiles, _ := basics["profiles"].([]interface{})
+	if user.GitHub != "" {
+		profiles = append([]interface{}{map[string]interface{}{"network": "GitHub", "url": user.GitHub}}, profiles...)
+	}
+	if user.LinkedIn != "" {
+		profiles = append([]interface{}{map[string]interface{}{"network": "LinkedIn", "url": user.LinkedIn}}, profiles...)
+	}
+	if len(profiles) > 0 {
+		basics["profiles"] = profiles
+	}
+	doc["basics"] = basics
+
+	experience := append([]model.Experience(nil), user.Experience...)
+	sort.SliceStable(experience, func(i, j int) bool { return newerPeriod(experience[i].Period, experience[j].Period) })
+	work := []interface{}{}
+	for _, e := range experience {
+		entry := copyJSONObject(e.Extensions)
+		setJSONResumeDates(entry, e.Years, e.Period)
+		for key, v := range map[string]string{
+			"name": e.Company, "position": e.Title, "location": e.Location, "summary": e.Description,
+		} {
+			if v != "" {
+				entry[key] = v
+			}
+		}
+		work = append(work, entry)
+	}
+	doc["work"] = work
+
+	schooling := append([]model.Education(nil), user.Education...)
+	sort.SliceStable(schooling, func(i, j int) bool { return newerPeriod(schooling[i].Period, schooling[j].Period) })
+	education := []interface{}{}
+	for _, e := range schooling {
+		entry := copyJSONObject(e.Extensions)
+		setJSONResumeDates(entry, e.Years, e.Period)
+		if jsonResumeDegree(entry) != e.Degree {
+			delete(entry, "area")
+			delete(entry, "studyType")
+			if e.Degree != "" {
+				entry["studyType"] = e.Degree
+			}
+		}
+		for key, v := range map[string]string{"institution": e.Institution, "location": e.Location, "score": e.GPA} {
+			if v != "" {
+				entry[key] = v
+			}
+		}
+		education = append(education, entry)
+	}
+	doc["education"] = education
+
+	doc["skills"] = exportSkills(splitSkills(user.Skills), doc["skills"])
+	return doc
+}
+
+// exportSkills lists the profile's skills in the groups of the last import
+// ({"name": "Web", "keywords": ["HTML", "CSS"]}) where they still apply, and
+// the rest one per entry.
+func exportSkills(skills []string, imported interface{}) []interface{} {
+	remaining := append([]string(nil), skills...)
+	take := func(name string) (string, bool) {
+		if canonical, ok := skillTaxonomy.Resolve(name); ok {
+			name = canonical
+		}
+		for i, s := range remaining {
+			if strings.EqualFold(s, name) {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				return s, true
+			}
+		}
+		return "", false
+	}
+
+	out := []interface{}{}
+	groups, _ := imported.([]interface{})
+	for _, g := range groups {
+		group, _ := g.(map[string]interface{})
+		if group == nil {
+			continue
+		}
+		group = copyJSONObject(group)
+
+		list, _ := group["keywords"].([]interface{})
+		if len(list) == 0 {
+			if name, ok := take(jsonString(group, "name")); ok {
+				group["name"] = name
+				out = append(out, group)
+			}
+			continue
+		}
+		keywords := []interface{}{}
+		for _, k := range list {
+			k, _ := k.(string)
+			if name, ok := take(k); ok {
+				keywords = append(keywords, name)
+			}
+		}
+		// Groups whose skills were all removed from the profile are dropped
+		if len(keywords) > 0 {
+			group["keywords"] = keywords
+			out = append(out, group)
+		}
+	}
+	for _, s := range remaining {
+		out = append(out, map[string]interface{}{"name": s})
+	}
+	return out
+}
+
+// jsonResumeYears turns an entry's startDate and endDate into the profile's
+// years text, e.g. "2020-01 - Present".
+func jsonResumeYears(entry map[string]interface{}) string {
+	start, end := jsonString(entry, "startDate"), jsonString(entry, "endDate")
+	switch {
+	case start == "" && end == "":
+		return ""
+	case start == "":
+		return end
+	case end == "":
+		return start + " - Present"
+	}
+	return start + " - " + end
+}
+
+// setJSONResumeDates writes an entry's dates back. Imported dates are kept
+// while the years they came from are unchanged; otherwise they are written
+// from the period at its precision.
+func setJSONResumeDates(entry map[string]interface{}, years string, period model.DateRange) {
+	if jsonResumeYears(entry) == years {
+		return
+	}
+	delete(entry, "startDate")
+	delete(entry, "endDate")
+
+	layout := "2006-01"
+	if period.Precision == model.PeriodPrecisionYear {
+		layout = "2006"
+	}
+	if period.Start != nil {
+		entry["startDate"] = period.Start.Format(layout)
+	}
+	if period.End != nil && !period.Ongoing {
+		entry["endDate"] = period.End.Format(layout)
+	}
+}
+
+// jsonResumeDegree joins an education entry's studyType and area, e.g.
+// "Bachelor in Computer Science".
+func jsonResumeDegree(entry map[string]interface{}) string {
+	studyType, area := jsonString(entry, "studyType"), jsonString(entry, "area")
+	if studyType != "" && area != "" {
+		return studyType + " in " + area
+	}
+	return studyType + area
+}
+
+// jsonResumeLocation formats a basics.location as the profile's location,
+// e.g. "Berlin, BE, DE".
+func jsonResumeLocation(loc map[string]interface{}) string {
+	var parts []string
+	for _, key := range []string{"city", "region", "countryCode"} {
+		if v := jsonString(loc, key); v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// newerPeriod orders ongoing entries first, then by start date, newest first.
+func newerPeriod(a, b model.DateRange) bool {
+	if a.Ongoing != b.Ongoing {
+		return a.Ongoing
+	}
+	if a.Start == nil || b.Start == nil {
+		return a.Start != nil
+	}
+	return a.Start.After(*b.Start)
+}
+
+func jsonString(m map[string]interface{}, key string) string {
+	v, _ := m[key].(string)
+	return strings.TrimSpace(v)
+}
+
+// takeJSONString removes a string field from m and returns it. Fields of
+// another type are left in place.
+func takeJSONString(m map[string]interface{}, key string) string {
+	v, ok := m[key].(string)
+	if ok {
+		delete(m, key)
+	}
+	return strings.TrimSpace(v)
+}
+
+func takeJSONObject(doc map[string]interface{}, key string) (map[string]interface{}, error) {
+	v, ok := doc[key]
+	if !ok || v == nil {
+		delete(doc, key)
+		return map[string]interface{}{}, nil
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: %s must be an object", ErrInvalidJSONResume, key)
+	}
+	delete(doc, key)
+	return obj, nil
+}
+
+// jsonObjects returns the objects of an array field, and whether doc has it.
+func jsonObjects(doc map[string]interface{}, key string) ([]map[string]interface{}, bool, error) {
+	v, ok := doc[key]
+	if !ok || v == nil {
+		return nil, false, nil
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, false, fmt.Errorf("%w: %s must be an array", ErrInvalidJSONResume, key)
+	}
+	objects := make([]map[string]interface{}, 0, len(list))
+	for i, item := range list {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false, fmt.Errorf("%w: %s[%d] must be an object", ErrInvalidJSONResume, key, i)
+		}
+		objects = append(objects, obj)
+	}
+	return objects, true, nil
+}
+
+func takeJSONObjects(doc map[string]interface{}, key string) ([]map[string]interface{}, bool, error) {
+	objects, ok, err := jsonObjects(doc, key)
+	if err == nil {
+		delete(doc, key)
+	}
+	return objects, ok, err
+}
+
+// copyJSONObject deep-copies a decoded JSON object, so stored extensions are
+// never modified in place.
+func copyJSONObject(m map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	if len(m) == 0 {
+		return out
+	}
+	data, err := json.Marshal(m)
+	if err == nil {
+		json.Unmarshal(data, &out)
+	}
+	return out
+}
+
+func dropEmpty(m map[string]interface{}) map[string]interface{} {
+	for k, v := range m {
+		if v == "" {
+			delete(m, k)
+		}
+	}
+	return m
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"expvar"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const parseCacheSweepInterval = time.Hour
+
+// parseCacheMetrics counts cache hits and misses; admins read them at /admin/parse-cache
+var parseCacheMetrics = expvar.NewMap("parse_cache")
+
+// ParseCacheMetrics returns the parse cache counters as a JSON object.
+func ParseCacheMetrics() string {
+	return parseCacheMetrics.String()
+}
+
+// parseCache, once set by UseParseCache, keeps Gemini results so the same
+// resume is not sent to Gemini twice
+var parseCache *ParseCache
+
+// UseParseCache makes ParseResume look up and store Gemini results in c.
+func UseParseCache(c *ParseCache) {
+	parseCache = c
+}
+
+// ParseCache stores Gemini parse results by the SHA-256 of the uploaded file
+// and of its extracted text, so a re-upload, or the same resume exported
+// again, skips the Gemini call. Entries expire after TTL and are ignored once
+// the prompt, schema or model changes.
+type ParseCache struct {
+	DB  *gorm.DB
+	TTL time.Duration
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewParseCache(db *gorm.DB, ttl time.Duration) *ParseCache {
+	c := &ParseCache{DB: db, TTL: ttl, done: make(chan struct{})}
+	c.wg.Add(1)
+	go c.sweep()
+	return c
+}
+
+// Close stops the sweeper.
+func (c *ParseCache) Close() {
+	close(c.done)
+	c.wg.Wait()
+}
+
+// parserFingerprint identifies what produced a Gemini result; any change to
+// the prompts, the schema, the model, text extraction or field scoring
+// invalidates cached results.
+var parserFingerprint = sync.OnceValue(func() string {
+	h := sha256.New()
+	h.Write([]byte(resumePrompt("", "", false)))
+	h.Write([]byte(languageInstruction(utils.LangSpanish, false) + languageInstruction(utils.LangSpanish, true) + languageInstruction("", true)))
+	h.Write([]byte(repairPrompt(ParsedResumeSchema, "", "", nil)))
+	h.Write([]byte(utils.GeminiModel))
+	h.Write([]byte(utils.ExtractorVersion))
+	h.Write([]byte(fieldScoringVersion))
+	return hex.EncodeToString(h.Sum(nil))[:16]
+})
+
+// fileKey returns the cache key of an uploaded file, or "" without a cache.
+func (c *ParseCache) fileKey(filePath string) string {
+	if c == nil {
+		return ""
+	}
+	sum, _, err := hashFile(filePath)
+	if err != nil {
+		return ""
+	}
+	return "file:" + sum
+}
+
+// translatedKey keeps results translated into English apart from those in
+// the resume's own language.
+func translatedKey(key string, opts ParseOptions) string {
+	if key == "" || !opts.ToEnglish {
+		return key
+	}
+	return key + ":en"
+}
+
+// textKey returns the cache key of extracted text, or "" without a cache.
+func (c *ParseCache) textKey(text string) string {
+	if c == nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(text))
+	return "text:" + hex.EncodeToString(sum[:])
+}
+
+// Get returns the cached result for key, or nil on a miss.
+func (c *ParseCache) Get(key string) *model.ParseResult {
+	if c == nil || key == "" {
+		return nil
+	}
+
+	var entry model.ParseCacheEntry
+	err := c.DB.Where("cache_key = ? AND fingerprint = ? AND expires_at > ?", key, parserFingerprint(), time.Now()).
+		First(&entry).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("⚠️ Parse cache lookup failed: %v", err)
+		}
+		return nil
+	}
+
+	kind, _, _ := strings.Cut(key, ":")
+	parseCacheMetrics.Add("hits", 1)
+	parseCacheMetrics.Add(kind+"_hits", 1)
+	entry.Result.Cached = true
+	return entry.Result
+}
+
+// miss counts a parse that had to go to Gemini.
+func (c *ParseCache) miss() {
+	if c != nil {
+		parseCacheMetrics.Add("misses", 1)
+	}
+}
+
+// Put stores a result under every given key.
+func (c *ParseCache) Put(result *model.ParseResult, keys ...string) {
+	if c == nil {
+		return
+	}
+
+	stored := *result
+	stored.Cached = false
+	now := time.Now()
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		entry := model.ParseCacheEntry{
+			Key:         key,
+			Fingerprint: parserFingerprint(),
+			Result:      &stored,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(c.TTL),
+		}
+		if err := c.DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(&entry).Error; err != nil {
+			log.Printf("⚠️ Failed to cache parse result: %v", err)
+			return
+		}
+		parseCacheMetrics.Add("stores", 1)
+	}
+}
+
+func (c *ParseCache) sweep() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(parseCacheSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			// Results of an older prompt or model are never read again either
+			res := c.DB.Where("expires_at < ? OR fingerprint <> ?", time.Now(), parserFingerprint()).
+				Delete(&model.ParseCacheEntry{})
+			if res.Error != nil {
+				log.Printf("⚠️ Failed to delete expired parse cache entries: %v", res.Error)
+			} else if res.RowsAffected > 0 {
+				parseCacheMetrics.Add("evictions", res.RowsAffected)
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	parseQueueSize     = 1024
+	parseSweepInterval = time.Minute
+	parseRetryBackoff  = 2 * time.Second // doubled on every retry
+)
+
+var ErrParseJobNotFound = errors.New("parse job not found")
+
+// ParseJobService runs resume parsing in the background. Jobs are stored in
+// the database, so queued work survives a restart, and a pool of workers
+// claims them from an in-memory queue.
+type ParseJobService struct {
+	DB *gorm.DB
+
+	MaxAttempts int
+	Retention   time.Duration // how long finished jobs are kept
+
+	queue chan uuid.UUID
+	done  chan struct{}
+	wg    sync.WaitGroup
+
+	mu      sync.Mutex
+	waiters map[uuid.UUID][]chan struct{} // closed when the job finishes
+}
+
+func NewParseJobService(db *gorm.DB, workers, maxAttempts int, retention time.Duration) *ParseJobService {
+	s := &ParseJobService{
+		DB:          db,
+		MaxAttempts: max(maxAttempts, 1),
+		Retention:   retention,
+		queue:       make(chan uuid.UUID, parseQueueSize),
+		done:        make(chan struct{}),
+		waiters:     make(map[uuid.UUID][]chan struct{}),
+	}
+
+	// Jobs that were running when the server stopped start over
+	if err := db.Model(&model.ParseJob{}).Where("status = ?", model.ParseJobRunning).
+		Update("status", model.ParseJobQueued).Error; err != nil {
+		log.Printf("⚠️ Failed to requeue interrupted parse jobs: %v", err)
+	}
+
+	for i := 0; i < max(workers, 1); i++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+	s.wg.Add(1)
+	go s.sweep()
+	return s
+}
+
+// Enqueue stores a parse job for an uploaded file. The service owns the file
+// from here on and removes it when the job finishes.
+func (s *ParseJobService) Enqueue(filePath string, opts ParseOptions) (*model.ParseJob, error) {
+	job := model.ParseJob{ID: uuid.New(), Status: model.ParseJobQueued, Mode: opts.Mode, RedactPII: opts.RedactPII, ToEnglish: opts.ToEnglish, FilePath: filePath}
+	if err := s.DB.Create(&job).Error; err != nil {
+		return nil, err
+	}
+	s.push(job.ID)
+	return &job, nil
+}
+
+// push hands a job to the workers. When the queue is full the job stays
+// queued in the database and the sweeper picks it up later.
+func (s *ParseJobService) push(id uuid.UUID) {
+	select {
+	case s.queue <- id:
+	default:
+		log.Println("⚠️ Parse queue full, job will be picked up by the next sweep")
+	}
+}
+
+func (s *ParseJobService) Get(id uuid.UUID) (*model.ParseJob, error) {
+	var job model.ParseJob
+	err := s.DB.First(&job, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrParseJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Wait returns the job once it finishes, or its current state when ctx ends first.
+func (s *ParseJobService) Wait(ctx context.Context, id uuid.UUID) (*model.ParseJob, error) {
+	ch := make(chan struct{})
+	s.mu.Lock()
+	s.waiters[id] = append(s.waiters[id], ch)
+	s.mu.Unlock()
+	defer s.unsubscribe(id, ch)
+
+	// Check after subscribing so a job finishing in between is not missed
+	job, err := s.Get(id)
+	if err != nil || job.Done() {
+		return job, err
+	}
+
+	select {
+	case <-ch:
+	case <-ctx.Done():
+	}
+	return s.Get(id)
+}
+
+func (s *ParseJobService) unsubscribe(id uuid.UUID, ch chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := s.waiters[id]
+	for i, c := range list {
+		if c == ch {
+			list = append(list[:i], list[i+1:]...)
+			break
+		}
+	}
+	if len(list) == 0 {
+		delete(s.waiters, id)
+	} else {
+		s.waiters[id] = list
+	}
+}
+
+func (s *ParseJobService) notify(id uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ch := range s.waiters[id] {
+		close(ch)
+	}
+	delete(s.waiters, id)
+}
+
+// Close stops taking jobs and waits for the workers to finish the ones in
+// hand. Jobs still queued are picked up again on the next start.
+func (s *ParseJobService) Close() {
+	close(s.done)
+	s.wg.Wait()
+}
+
+func (s *ParseJobService) work() {
+	defer s.wg.Done()
+	for {
+		select {
+		case id := <-s.queue:
+			s.process(id)
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *ParseJobService) process(id uuid.UUID) {
+	// Claim the job; another worker may already have it after a sweep re-queued it
+	claim := s.DB.Model(&model.ParseJob{}).Where("id = ? AND status = ?", id, model.ParseJobQueued).
+		Update("status", model.ParseJobRunning)
+	if claim.Error != nil || claim.RowsAffected == 0 {
+		return
+	}
+
+	job, err := s.Get(id)
+	if err != nil {
+		log.Printf("⚠️ Parse job %s vanished: %v", id, err)
+		return
+	}
+
+	var result *model.ParseResult
+	for {
+		job.Attempts++
+		result, err = ParseResume(job.FilePath, ParseOptions{Mode: job.Mode, RedactPII: job.RedactPII, ToEnglish: job.ToEnglish})
+		if err == nil || !retryableParseError(err) || job.Attempts >= s.MaxAttempts {
+			break
+		}
+
+		log.Printf("⚠️ Parse job %s attempt %d failed, retrying: %v", id, job.Attempts, err)
+		backoff := parseRetryBackoff << (job.Attempts - 1)
+		select {
+		case <-time.After(backoff):
+		case <-s.done:
+			// Shutting down: leave the job for the next start
+			s.DB.Model(job).Updates(map[string]interface{}{"status": model.ParseJobQueued, "attempts": job.Attempts})
+			return
+		}
+	}
+
+	now := time.Now()
+	expires := now.Add(s.Retention)
+	job.CompletedAt, job.ExpiresAt = &now, &expires
+	if err != nil {
+		job.Status, job.Error = model.ParseJobFailed, err.Error()
+	} else {
+		job.Status, job.Result = model.ParseJobSucceeded, result
+	}
+	// A struct update, so the result goes through its JSON serializer
+	if err := s.DB.Model(job).Select("status", "error", "result", "attempts", "completed_at", "expires_at").
+		Updates(job).Error; err != nil {
+		log.Printf("❌ Failed to save parse job %s: %v", id, err)
+	}
+
+	os.Remove(job.FilePath)
+	s.notify(id)
+}
+
+// retryableParseError reports whether a failed parse may succeed on retry.
+// Bad input fails the same way every time; Gemini errors and timeouts may not.
+func retryableParseError(err error) bool {
+	return !utils.IsRejectedDocument(err) && !errors.Is(err, ErrInvalidParserMode) &&
+		!errors.Is(err, os.ErrNotExist)
+}
+
+// sweep re-queues jobs the in-memory queue lost and deletes expired ones.
+func (s *ParseJobService) sweep() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(parseSweepInterval)
+	defer ticker.Stop()
+
+	s.requeue(time.Now())
+	for {
+		select {
+		case <-ticker.C:
+			s.requeue(time.Now().Add(-parseSweepInterval))
+			s.deleteExpired()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *ParseJobService) requeue(olderThan time.Time) {
+	var ids []uuid.UUID
+	if err := s.DB.Model(&model.ParseJob{}).Where("status = ? AND updated_at <= ?", model.ParseJobQueued, olderThan).
+		Order("created_at").Limit(parseQueueSize/2).Pluck("id", &ids).Error; err != nil {
+		log.Printf("⚠️ Failed to load queued parse jobs: %v", err)
+		return
+	}
+	for _, id := range ids {
+		s.push(id)
+	}
+}
+
+func (s *ParseJobService) deleteExpired() {
+	res := s.DB.Where("expires_at < ?", time.Now()).Delete(&model.ParseJob{})
+	if res.Error != nil {
+		log.Printf("⚠️ Failed to delete expired parse jobs: %v", res.Error)
+	} else if res.RowsAffected > 0 {
+		log.Printf("🧹 Deleted %d expired parse jobs", res.RowsAffected)
+	}
+}
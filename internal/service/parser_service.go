@@ -1,124 +1,273 @@
 package service
 
 import (
-	"bytes"
+	_ "embed"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"log"
+	"strings"
 
-	"github.com/satyam-svg/resume-parser/config"
+	"github.com/satyam-svg/resume-parser/internal/model"
 	"github.com/satyam-svg/resume-parser/internal/utils"
 )
 
-func ParseResume(filePath string) (string, error) {
-	// 1. Extract text from PDF
-	text, err := utils.ExtractTextFromPDF(filePath)
-	if err != nil {
-		return "", err
+var (
+	ErrInvalidResumeOutput = errors.New("parser output does not match the resume schema")
+	ErrInvalidParserMode   = errors.New("invalid parser mode. Must be auto, llm or heuristic")
+)
+
+// Resume parser modes
+const (
+	ParserModeAuto      = "auto"      // Gemini, falling back to heuristics when it fails
+	ParserModeLLM       = "llm"       // Gemini only
+	ParserModeHeuristic = "heuristic" // patterns only, no network
+)
+
+// Parsers reported in ParseResult.Parser
+const (
+	parserGemini    = "gemini"
+	parserHeuristic = "heuristic"
+)
+
+// maxRepairAttempts is how many times invalid output is sent back to Gemini to be fixed
+const maxRepairAttempts = 2
+
+//go:embed schema/parsed_resume.v1.json
+var ParsedResumeSchema []byte
+
+var parsedResumeSchema map[string]interface{}
+
+func init() {
+	if err := json.Unmarshal(ParsedResumeSchema, &parsedResumeSchema); err != nil {
+		panic("invalid parsed resume schema: " + err.Error())
 	}
+}
 
-	// 2. Sanitize raw PDF text
-	sanitized := utils.SanitizeText(text)
+func ValidateParserMode(mode string) error {
+	switch mode {
+	case ParserModeAuto, ParserModeLLM, ParserModeHeuristic:
+		return nil
+	}
+	return ErrInvalidParserMode
+}
 
-	// 3. Prompt sent to Gemini
-	prompt := fmt.Sprintf(`You are a resume parsing assistant. Extract the following information from the given resume and return it as valid JSON (DO NOT include "json" prefix):
-{
-  "full_name": "",
-  "title": "",
-  "experience": [
-    {
-      "company": "",
-      "location": "",
-      "title": "",
-      "years": "",
-      "description": ""
-    }
-  ],
-  "education": [
-    {
-      "institution": "",
-      "location": "",
-      "degree": "",
-      "gpa": "",
-      "years": ""
-    }
-  ],
-  "skills": [""],
-  "location": "",
-  "email": "",
-  "phone": "",
-  "current_company": "",
-  "linkedin": "",
-  "github": "",
-  "portfolio": ""
+// ParseOptions control how ParseResume parses a resume.
+type ParseOptions struct {
+	Mode      string // auto, llm or heuristic
+	RedactPII bool   // send Gemini the text with PII replaced by placeholders
+	ToEnglish bool   // have Gemini translate values into English
 }
 
-Resume:
-"%s"`, sanitized)
+// ParseResume extracts a resume's text and parses it as opts say.
+func ParseResume(filePath string, opts ParseOptions) (*model.ParseResult, error) {
+	mode := opts.Mode
+	if err := ValidateParserMode(mode); err != nil {
+		return nil, err
+	}
 
-	// 4. Create API request
-	reqBody := map[string]interface{}{
-		"contents": []map[string]interface{}{
-			{
-				"parts": []map[string]string{
-					{"text": prompt},
-				},
-			},
-		},
+	// A file parsed before is answered from the cache without extracting it
+	fileKey := ""
+	if mode != ParserModeHeuristic {
+		fileKey = translatedKey(parseCache.fileKey(filePath), opts)
+		if result := parseCache.Get(fileKey); result != nil {
+			return result, nil
+		}
 	}
 
-	jsonData, err := json.Marshal(reqBody)
+	// 1. Extract text (format is sniffed from the content)
+	doc, err := utils.ExtractDocument(filePath)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	text, format := doc.Text, doc.Format
+	language, confidence := utils.DetectLanguage(text)
 
-	// 5. Gemini API Key & Endpoint
-	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1/models/gemini-1.5-flash:generateContent?key=%s", config.AppConfig.GeminiAPIKey)
+	if mode == ParserModeHeuristic {
+		return parseHeuristically(doc, language, confidence, opts, ""), nil
+	}
 
-	// 6. Make HTTP request
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	// So is the same text in another file, e.g. a resume exported again
+	textKey := translatedKey(parseCache.textKey(text), opts)
+	if result := parseCache.Get(textKey); result != nil {
+		result.Format = format
+		parseCache.Put(result, fileKey)
+		return result, nil
+	}
+	parseCache.miss()
+
+	result, err := parseWithGemini(text, format, language, opts)
+	if err == nil {
+		result.Language, result.LanguageConfidence = language, confidence
+		scoreFields(result, doc)
+		parseCache.Put(result, fileKey, textKey)
+	}
+	if err != nil && mode == ParserModeAuto {
+		log.Printf("⚠️ Gemini resume parsing failed, using heuristics: %v", err)
+		return parseHeuristically(doc, language, confidence, opts, err.Error()), nil
+	}
+	return result, err
+}
+
+func parseWithGemini(text, format, language string, opts ParseOptions) (*model.ParseResult, error) {
+	// 2. Replace PII with placeholders and sanitize raw document text
+	var redaction *Redaction
+	if opts.RedactPII {
+		redaction = RedactPII(text, language)
+		text = redaction.Text
+	}
+	sanitized := utils.SanitizeText(text)
+
+	// 3. Ask Gemini for the structured resume
+	raw, err := utils.CallGeminiText(resumePrompt(sanitized, language, opts.ToEnglish))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
+	// 4. Decode strictly; send invalid output back with the problems found
+	resume, problems := decodeParsedResume(raw)
+	repairs := 0
+	for len(problems) > 0 && repairs < maxRepairAttempts {
+		repairs++
+		raw, err = utils.CallGeminiText(repairPrompt(ParsedResumeSchema, sanitized, raw, problems))
+		if err != nil {
+			return nil, err
+		}
+		resume, problems = decodeParsedResume(raw)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidResumeOutput, strings.Join(problems, "; "))
+	}
 
-	// 7. Handle non-200 errors
-	if resp.StatusCode != http.StatusOK {
-		return "", errors.New("Gemini API error: " + string(body))
+	// 5. Put the redacted values back, locally
+	result := &model.ParseResult{
+		SchemaVersion: model.ResumeSchemaVersion,
+		Format:        format,
+		Parser:        parserGemini,
+		Repairs:       repairs,
+		Resume:        resume,
+	}
+	if opts.ToEnglish {
+		result.TranslatedTo = utils.LangEnglish
 	}
+	if redaction != nil {
+		redaction.Restore(resume)
+		result.Redactions = redaction.Count()
+	}
+	normalizePeriods(resume)
+	return result, nil
+}
 
-	// 8. Parse response JSON
-	var parsed map[string]interface{}
-	if err := json.Unmarshal(body, &parsed); err != nil {
-		return "", err
+// parseHeuristically runs the offline parser. Its output is checked against
+// the same schema, but problems are reported as warnings since there is no
+// model to repair them. It cannot translate, so values stay in the resume's
+// language even when opts ask for English.
+func parseHeuristically(doc *utils.Document, language string, confidence float64, opts ParseOptions, fallbackReason string) *model.ParseResult {
+	resume := ParseResumeHeuristically(doc.Text, language)
+	normalizePeriods(resume)
+
+	var warnings []string
+	if data, err := json.Marshal(resume); err == nil {
+		var doc interface{}
+		json.Unmarshal(data, &doc)
+		warnings = utils.ValidateJSONSchema(parsedResumeSchema, doc)
+	}
+	if opts.ToEnglish && language != "" && language != utils.LangEnglish {
+		warnings = append(warnings, fmt.Sprintf("values are in %s: the heuristic parser cannot translate them into English", utils.LanguageNames[language]))
 	}
 
-	candidates, ok := parsed["candidates"].([]interface{})
-	if !ok || len(candidates) == 0 {
-		return "", errors.New("no candidates returned")
+	result := &model.ParseResult{
+		SchemaVersion:      model.ResumeSchemaVersion,
+		Format:             doc.Format,
+		Parser:             parserHeuristic,
+		FallbackReason:     fallbackReason,
+		Warnings:           warnings,
+		Resume:             resume,
+		Language:           language,
+		LanguageConfidence: confidence,
+	}
+	scoreFields(result, doc)
+	return result
+}
+
+// normalizePeriods fills in the structured period of every dated entry,
+// replacing whatever the model put there.
+func normalizePeriods(resume *model.ParsedResume) {
+	for i := range resume.Experience {
+		resume.Experience[i].Period = parsePeriod(resume.Experience[i].Years)
+	}
+	for i := range resume.Education {
+		resume.Education[i].Period = parsePeriod(resume.Education[i].Years)
+	}
+}
+
+func parsePeriod(years string) *model.DateRange {
+	if strings.TrimSpace(years) == "" {
+		return nil
 	}
+	period := utils.ParseDateRange(years)
+	return &period
+}
+
+// decodeParsedResume validates an LLM reply against the schema and decodes it,
+// returning the problems found if it does not conform.
+func decodeParsedResume(raw string) (*model.ParsedResume, []string) {
+	jsonStr := utils.ExtractJSONObject(raw)
 
-	content, ok := candidates[0].(map[string]interface{})["content"].(map[string]interface{})
-	if !ok {
-		return "", errors.New("invalid content format")
+	var doc interface{}
+	if err := json.Unmarshal([]byte(jsonStr), &doc); err != nil {
+		return nil, []string{"not valid JSON: " + err.Error()}
+	}
+	if problems := utils.ValidateJSONSchema(parsedResumeSchema, doc); len(problems) > 0 {
+		return nil, problems
 	}
 
-	parts, ok := content["parts"].([]interface{})
-	if !ok || len(parts) == 0 {
-		return "", errors.New("invalid parts format")
+	dec := json.NewDecoder(strings.NewReader(jsonStr))
+	dec.DisallowUnknownFields()
+	var resume model.ParsedResume
+	if err := dec.Decode(&resume); err != nil {
+		return nil, []string{err.Error()}
 	}
+	return &resume, nil
+}
 
-	textOut, ok := parts[0].(map[string]interface{})["text"].(string)
-	if !ok {
-		return "", errors.New("no text found in response")
+func resumePrompt(resume, language string, toEnglish bool) string {
+	return fmt.Sprintf(`You are a resume parsing assistant. Extract the information in the resume below and return a single JSON object that conforms to this JSON Schema. Return only the JSON, with no Markdown fence or commentary. Use "" for missing text fields and [] for missing lists. Personal details may have been replaced with placeholders such as [NAME_1], [PHONE_1] or email1@redacted.invalid; copy placeholders into the fields exactly as written.%s
+
+Schema:
+%s
+
+Resume:
+"%s"`, languageInstruction(language, toEnglish), ParsedResumeSchema, resume)
+}
+
+// languageInstruction tells Gemini the language the resume was detected in
+// and whether to translate its values.
+func languageInstruction(language string, toEnglish bool) string {
+	name := utils.LanguageNames[language]
+	switch {
+	case toEnglish && name != "" && language != utils.LangEnglish:
+		return fmt.Sprintf(` The resume is written in %s. Translate every value into English, except names, email addresses, phone numbers, URLs, company and institution names and skill names.`, name)
+	case toEnglish:
+		return ` Translate any value not written in English into English, except names, email addresses, phone numbers, URLs, company and institution names and skill names.`
+	case name != "" && language != utils.LangEnglish:
+		return fmt.Sprintf(` The resume is written in %s; its section headings are in %s too. Copy values in %s as written, without translating them.`, name, name, name)
 	}
+	return ""
+}
+
+func repairPrompt(schema []byte, source, previous string, problems []string) string {
+	return fmt.Sprintf(`Your previous answer does not conform to the JSON Schema below. Fix it and return only the corrected JSON object, with no Markdown fence or commentary. Take any missing or wrong values from the source document; do not invent information that is in neither the document nor your previous answer.
+
+Problems:
+- %s
+
+Schema:
+%s
 
-	// 9. Clean markdown ```json ... ```
-	cleaned := utils.CleanGeminiResponse(textOut)
+Source document:
+"%s"
 
-	return cleaned, nil
+Previous answer:
+%s`, strings.Join(problems, "\n- "), schema, source, previous)
 }
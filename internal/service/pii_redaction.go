@@ -0,0 +1,176 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+)
+
+// PII redaction keeps personal details out of the text sent to Gemini. Each
+// value is replaced with a numbered placeholder, and the placeholders are
+// swapped back for the original values in the parsed resume, locally.
+
+var (
+	personalURLPattern = regexp.MustCompile(`(?i)\b(https?://|www\.)[^\s|,;()<>]+`)
+	streetPattern      = regexp.MustCompile(`\b\d{1,5}[A-Za-z]?,?\s+([A-Z][\w'.-]*\s+){1,4}(Street|St|Avenue|Ave|Road|Rd|Lane|Ln|Boulevard|Blvd|Drive|Dr|Way|Court|Ct|Place|Pl|Terrace|Square|Sq)\b\.?` +
+		`|\p{Lu}[\p{L}-]*(straße|strasse|str\.|weg|gasse|platz|allee|ring|damm| Straße| Strasse| Weg| Platz| Allee)\s+\d{1,4}[a-z]?\b` +
+		`|\b\d{1,4},?\s+(rue|avenue|boulevard|allée|place|chemin|quai)\s+(\p{L}[\p{L}'-]*\s?){1,4}` +
+		`|\b(Via|Viale|Calle|Avenida|Plaza|Rua|Rue)\s+(\p{L}[\p{L}'-]*\s+){1,3}\d{1,4}\b`)
+	ukPostcodePattern = regexp.MustCompile(`\b[A-Z]{1,2}\d[A-Z\d]?\s?\d[A-Z]{2}\b`)
+	// ", IL 62704" and ", 10115 Berlin"; a lone five-digit figure is left alone
+	zipCodePattern   = regexp.MustCompile(`,\s*[A-Z]{2}\s+(\d{5}(-\d{4})?)\b`)
+	postcodePattern  = regexp.MustCompile(`,\s*(\d{5})\s+\p{Lu}`)
+	birthDatePattern = regexp.MustCompile(`(?i)\b(date of birth|d\.?o\.?b\.?|born( on)?|geburtsdatum|né\(?e?\)? le)\s*[:\-]?\s*` +
+		`(\d{1,2}[./-]\d{1,2}[./-]\d{2,4}|\d{4}-\d{2}-\d{2}|\d{1,2}(st|nd|rd|th)?\s+[a-z]+\.?\s+\d{4}|[a-z]+\.?\s+\d{1,2}(st|nd|rd|th)?,?\s+\d{4})`)
+)
+
+// Placeholder kinds. Emails get an address-shaped placeholder so the reply
+// still passes the schema's email pattern.
+const (
+	piiName    = "NAME"
+	piiEmail   = "EMAIL"
+	piiPhone   = "PHONE"
+	piiURL     = "URL"
+	piiAddress = "ADDRESS"
+	piiBirth   = "BIRTHDATE"
+)
+
+// Redaction is resume text with its PII replaced by placeholders.
+type Redaction struct {
+	Text   string
+	values map[string]string // placeholder -> original value
+}
+
+type piiSpan struct {
+	start, end int
+	kind       string
+}
+
+// RedactPII replaces names, email addresses, phone numbers, profile and
+// personal URLs, street addresses, postcodes and birth dates in text, a
+// resume in language (ISO 639-1, "" if unknown). The same value always gets
+// the same placeholder.
+func RedactPII(text, language string) *Redaction {
+	var spans []piiSpan
+	add := func(kind string, locs [][]int) {
+		for _, loc := range locs {
+			spans = append(spans, piiSpan{loc[0], loc[1], kind})
+		}
+	}
+
+	add(piiEmail, emailPattern.FindAllStringIndex(text, -1))
+	add(piiURL, linkedInPattern.FindAllStringIndex(text, -1))
+	add(piiURL, gitHubPattern.FindAllStringIndex(text, -1))
+	add(piiURL, personalURLPattern.FindAllStringIndex(text, -1))
+	for _, loc := range phonePattern.FindAllStringIndex(text, -1) {
+		// Same rule as the heuristic parser: date ranges are not phone numbers
+		p := text[loc[0]:loc[1]]
+		if digits := countDigits(p); digits >= 9 && digits <= 15 && !dateRangePattern.MatchString(p) {
+			spans = append(spans, piiSpan{loc[0], loc[1], piiPhone})
+		}
+	}
+	add(piiAddress, streetPattern.FindAllStringIndex(text, -1))
+	add(piiAddress, ukPostcodePattern.FindAllStringIndex(text, -1))
+	for _, m := range append(zipCodePattern.FindAllStringSubmatchIndex(text, -1), postcodePattern.FindAllStringSubmatchIndex(text, -1)...) {
+		spans = append(spans, piiSpan{m[2], m[3], piiAddress}) // the code, not the comma
+	}
+	for _, m := range birthDatePattern.FindAllStringSubmatchIndex(text, -1) {
+		spans = append(spans, piiSpan{m[6], m[7], piiBirth}) // the date, not the label
+	}
+	if name := ParseResumeHeuristically(text, language).FullName; len(strings.Fields(name)) >= 2 {
+		for i := 0; ; {
+			j := strings.Index(text[i:], name)
+			if j < 0 {
+				break
+			}
+			spans = append(spans, piiSpan{i + j, i + j + len(name), piiName})
+			i += j + len(name)
+		}
+	}
+
+	// Earlier spans win; of two starting together the longer one does
+	sort.Slice(spans, func(a, b int) bool {
+		if spans[a].start != spans[b].start {
+			return spans[a].start < spans[b].start
+		}
+		return spans[a].end > spans[b].end
+	})
+
+	r := &Redaction{values: map[string]string{}}
+	placeholders := map[string]string{} // kind + value -> placeholder
+	counts := map[string]int{}
+	var b strings.Builder
+	last := 0
+	for _, sp := range spans {
+		raw := text[sp.start:sp.end]
+		value := strings.TrimSpace(raw)
+		sp.start += len(raw) - len(strings.TrimLeftFunc(raw, unicode.IsSpace))
+		sp.end = sp.start + len(value)
+		if sp.start < last || value == "" {
+			continue // overlaps a span already replaced
+		}
+		ph, ok := placeholders[sp.kind+"\x00"+value]
+		if !ok {
+			counts[sp.kind]++
+			ph = placeholder(sp.kind, counts[sp.kind])
+			placeholders[sp.kind+"\x00"+value] = ph
+			r.values[ph] = value
+		}
+		b.WriteString(text[last:sp.start])
+		b.WriteString(ph)
+		last = sp.end
+	}
+	b.WriteString(text[last:])
+	r.Text = b.String()
+	return r
+}
+
+func placeholder(kind string, n int) string {
+	if kind == piiEmail {
+		return fmt.Sprintf("email%d@redacted.invalid", n)
+	}
+	return fmt.Sprintf("[%s_%d]", kind, n)
+}
+
+// Count is the number of distinct values redacted.
+func (r *Redaction) Count() int {
+	return len(r.values)
+}
+
+// Restore puts the original values back into every field of a resume parsed
+// from the redacted text.
+func (r *Redaction) Restore(resume *model.ParsedResume) {
+	if resume == nil || len(r.values) == 0 {
+		return
+	}
+
+	pairs := make([]string, 0, 2*len(r.values))
+	for ph, value := range r.values {
+		pairs = append(pairs, ph, value)
+	}
+	rep := strings.NewReplacer(pairs...)
+
+	for _, f := range []*string{&resume.FullName, &resume.Title, &resume.Location, &resume.Email, &resume.Phone,
+		&resume.CurrentCompany, &resume.LinkedIn, &resume.GitHub, &resume.Portfolio} {
+		*f = rep.Replace(*f)
+	}
+	for i := range resume.Skills {
+		resume.Skills[i] = rep.Replace(resume.Skills[i])
+	}
+	for i := range resume.Experience {
+		e := &resume.Experience[i]
+		for _, f := range []*string{&e.Company, &e.Location, &e.Title, &e.Years, &e.Description} {
+			*f = rep.Replace(*f)
+		}
+	}
+	for i := range resume.Education {
+		e := &resume.Education[i]
+		for _, f := range []*string{&e.Institution, &e.Location, &e.Degree, &e.GPA, &e.Years} {
+			*f = rep.Replace(*f)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+)
+
+func TestRedactPIIFormats(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		language string
+		redacted []string // values that must not reach the redacted text
+		kept     []string // text that must survive redaction
+	}{
+		{
+			name:     "emails",
+			text:     "Contact: jane.doe+jobs@example.co.uk or j_doe@mail-server.com",
+			redacted: []string{"jane.doe+jobs@example.co.uk", "j_doe@mail-server.com"},
+		},
+		{
+			name:     "international phone numbers",
+			text:     "Phone: +1 (415) 555-0132\nMobile: +44 20 7946 0958\nTel: +49 30 12345678\nTél: +33 1 42 68 53 00",
+			redacted: []string{"+1 (415) 555-0132", "+44 20 7946 0958", "+49 30 12345678", "+33 1 42 68 53 00"},
+		},
+		{
+			name:     "local phone numbers",
+			text:     "Phone: (415) 555-0132\nUK: 020 7946 0958\nDE: 030 1234567\nFR: 01 42 68 53 00",
+			redacted: []string{"(415) 555-0132", "020 7946 0958", "030 1234567", "01 42 68 53 00"},
+		},
+		{
+			name: "date ranges are not phone numbers",
+			text: "Engineer at Acme, 2019 - 2023",
+			kept: []string{"2019 - 2023"},
+		},
+		{
+			name:     "US address and ZIP code",
+			text:     "Address: 1600 Amphitheatre Parkway Drive, Mountain View, CA 94043",
+			redacted: []string{"1600 Amphitheatre Parkway Drive", "CA 94043"},
+			kept:     []string{"Address:"},
+		},
+		{
+			name:     "UK address and postcode",
+			text:     "221B Baker Street, London NW1 6XE",
+			redacted: []string{"221B Baker Street", "NW1 6XE"},
+		},
+		{
+			name:     "German address and postcode",
+			text:     "Anschrift: Hauptstraße 12, 10115 Berlin",
+			language: "de",
+			redacted: []string{"Hauptstraße 12", "10115"},
+			kept:     []string{"Anschrift:"},
+		},
+		{
+			name:     "French address and postcode",
+			text:     "Adresse : 8 rue de Rivoli, 75004 Paris",
+			language: "fr",
+			redacted: []string{"8 rue de Rivoli", "75004"},
+		},
+		{
+			name: "figures in bullets are not postcodes",
+			text: "Served 10000 Customers a day and cut costs by 25%",
+			kept: []string{"10000 Customers", "25%"},
+		},
+		{
+			name:     "birth dates",
+			text:     "Date of birth: 04/07/1990\nDOB: 1990-07-04\nBorn on 4 July 1990\nGeburtsdatum: 04.07.1990",
+			redacted: []string{"04/07/1990", "1990-07-04", "4 July 1990", "04.07.1990"},
+			kept:     []string{"Date of birth:", "Geburtsdatum:"},
+		},
+		{
+			name:     "names",
+			text:     "Jane Doe\nSoftware Engineer\njane@example.com\n\nExperience\nBackend Engineer at Acme, 2019 - 2023\nWorked with Jane Doe's team on payments",
+			redacted: []string{"Jane Doe"},
+			kept:     []string{"Software Engineer", "Acme"},
+		},
+		{
+			name:     "personal URLs",
+			text:     "linkedin.com/in/jane-doe | github.com/janedoe | https://janedoe.dev/blog",
+			redacted: []string{"linkedin.com/in/jane-doe", "github.com/janedoe", "https://janedoe.dev/blog"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := RedactPII(tt.text, tt.language)
+			for _, v := range tt.redacted {
+				if strings.Contains(r.Text, v) {
+					t.Errorf("%q was not redacted:\n%s", v, r.Text)
+				}
+			}
+			for _, v := range tt.kept {
+				if !strings.Contains(r.Text, v) {
+					t.Errorf("%q was redacted:\n%s", v, r.Text)
+				}
+			}
+		})
+	}
+}
+
+func TestRedactPIIPlaceholdersAreStable(t *testing.T) {
+	text := "Jane Doe\njane@example.com\n+44 20 7946 0958\n\nReferee: call +44 20 7946 0958 or write to jane@example.com\nOther: sam@example.com"
+	r := RedactPII(text, "en")
+
+	if got := strings.Count(r.Text, "email1@redacted.invalid"); got != 2 {
+		t.Errorf("repeated email got %d copies of one placeholder, want 2:\n%s", got, r.Text)
+	}
+	if !strings.Contains(r.Text, "email2@redacted.invalid") {
+		t.Errorf("second email did not get its own placeholder:\n%s", r.Text)
+	}
+	if got := strings.Count(r.Text, "[PHONE_1]"); got != 2 {
+		t.Errorf("repeated phone got %d copies of one placeholder, want 2:\n%s", got, r.Text)
+	}
+	if r.Count() != 4 {
+		t.Errorf("Count() = %d, want 4 distinct values (name, two emails, phone)", r.Count())
+	}
+
+	// The same text always gets the same placeholders
+	if again := RedactPII(text, "en"); again.Text != r.Text {
+		t.Errorf("redacting twice differs:\n%s\n---\n%s", r.Text, again.Text)
+	}
+
+	// Email placeholders still look like addresses to the schema's pattern
+	if !emailPattern.MatchString(placeholder(piiEmail, 1)) {
+		t.Errorf("email placeholder %q does not match the email pattern", placeholder(piiEmail, 1))
+	}
+}
+
+func TestRedactionRestore(t *testing.T) {
+	text := "Jane Doe\njane@example.com | +1 (415) 555-0132 | linkedin.com/in/jane-doe | github.com/janedoe\n" +
+		"221B Baker Street, London NW1 6XE\nDate of birth: 04/07/1990"
+	r := RedactPII(text, "en")
+
+	ph := map[string]string{} // original value -> placeholder
+	for p, v := range r.values {
+		ph[v] = p
+	}
+	for _, v := range []string{"Jane Doe", "jane@example.com", "+1 (415) 555-0132", "linkedin.com/in/jane-doe", "github.com/janedoe", "221B Baker Street", "NW1 6XE", "04/07/1990"} {
+		if ph[v] == "" {
+			t.Fatalf("%q has no placeholder; values: %v", v, r.values)
+		}
+	}
+
+	// A reply from Gemini with a placeholder in every field
+	resume := &model.ParsedResume{
+		FullName:       ph["Jane Doe"],
+		Title:          "Engineer (" + ph["Jane Doe"] + ")",
+		Location:       ph["221B Baker Street"] + ", London " + ph["NW1 6XE"],
+		Email:          ph["jane@example.com"],
+		Phone:          ph["+1 (415) 555-0132"],
+		CurrentCompany: "Acme",
+		LinkedIn:       ph["linkedin.com/in/jane-doe"],
+		GitHub:         ph["github.com/janedoe"],
+		Portfolio:      ph["github.com/janedoe"],
+		Skills:         []string{"Go", ph["Jane Doe"]},
+		Experience: []model.ParsedExperience{{
+			Company: ph["Jane Doe"], Location: ph["221B Baker Street"], Title: ph["Jane Doe"],
+			Years: ph["04/07/1990"], Description: "Reachable at " + ph["jane@example.com"],
+		}},
+		Education: []model.ParsedEducation{{
+			Institution: ph["Jane Doe"], Location: ph["NW1 6XE"], Degree: ph["Jane Doe"],
+			GPA: ph["04/07/1990"], Years: ph["04/07/1990"],
+		}},
+	}
+	r.Restore(resume)
+
+	want := &model.ParsedResume{
+		FullName:       "Jane Doe",
+		Title:          "Engineer (Jane Doe)",
+		Location:       "221B Baker Street, London NW1 6XE",
+		Email:          "jane@example.com",
+		Phone:          "+1 (415) 555-0132",
+		CurrentCompany: "Acme",
+		LinkedIn:       "linkedin.com/in/jane-doe",
+		GitHub:         "github.com/janedoe",
+		Portfolio:      "github.com/janedoe",
+		Skills:         []string{"Go", "Jane Doe"},
+		Experience: []model.ParsedExperience{{
+			Company: "Jane Doe", Location: "221B Baker Street", Title: "Jane Doe",
+			Years: "04/07/1990", Description: "Reachable at jane@example.com",
+		}},
+		Education: []model.ParsedEducation{{
+			Institution: "Jane Doe", Location: "NW1 6XE", Degree: "Jane Doe",
+			GPA: "04/07/1990", Years: "04/07/1990",
+		}},
+	}
+	if got, exp := flattenResume(resume), flattenResume(want); got != exp {
+		t.Errorf("Restore left placeholders or changed values:\ngot  %s\nwant %s", got, exp)
+	}
+}
+
+func TestRedactionRestoreWithoutValues(t *testing.T) {
+	r := RedactPII("Experience\nBuilt payment APIs", "en")
+	resume := &model.ParsedResume{FullName: "[NAME_1]"}
+	r.Restore(resume)
+	if resume.FullName != "[NAME_1]" {
+		t.Errorf("Restore changed a resume with nothing redacted: %q", resume.FullName)
+	}
+	r.Restore(nil)
+}
+
+// flattenResume joins every text field of a resume, for comparison.
+func flattenResume(r *model.ParsedResume) string {
+	fields := []string{r.FullName, r.Title, r.Location, r.Email, r.Phone, r.CurrentCompany, r.LinkedIn, r.GitHub, r.Portfolio}
+	fields = append(fields, r.Skills...)
+	for _, e := range r.Experience {
+		fields = append(fields, e.Company, e.Location, e.Title, e.Years, e.Description)
+	}
+	for _, e := range r.Education {
+		fields = append(fields, e.Institution, e.Location, e.Degree, e.GPA, e.Years)
+	}
+	return strings.Join(fields, " | ")
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidMergeChoice = errors.New("invalid merge choice. Must be merge, replace or skip")
+	ErrUnknownLanguage    = errors.New("unknown language")
+)
+
+type ProfileService struct {
+	DB *gorm.DB
+}
+
+// profileField reads and writes one basic profile field. Email is left out:
+// it is the login and never changes through a resume.
+type profileField struct {
+	name   string // JSON name
+	column string
+	get    func(u *model.User) string
+	set    func(u *model.User, v string)
+	from   func(p *model.ParsedResume) string
+}
+
+var profileFields = []profileField{
+	{"full_name", "full_name", func(u *model.User) string { return u.FullName }, func(u *model.User, v string) { u.FullName = v }, func(p *model.ParsedResume) string { return p.FullName }},
+	{"title", "title", func(u *model.User) string { return u.Title }, func(u *model.User, v string) { u.Title = v }, func(p *model.ParsedResume) string { return p.Title }},
+	{"location", "location", func(u *model.User) string { return u.Location }, func(u *model.User, v string) { u.Location = v }, func(p *model.ParsedResume) string { return p.Location }},
+	{"phone", "phone", func(u *model.User) string { return u.Phone }, func(u *model.User, v string) { u.Phone = v }, func(p *model.ParsedResume) string { return p.Phone }},
+	{"current_company", "current_company", func(u *model.User) string { return u.CurrentCompany }, func(u *model.User, v string) { u.CurrentCompany = v }, func(p *model.ParsedResume) string { return p.CurrentCompany }},
+	{"linkedin", "linked_in", func(u *model.User) string { return u.LinkedIn }, func(u *model.User, v string) { u.LinkedIn = v }, func(p *model.ParsedResume) string { return p.LinkedIn }},
+	{"github", "git_hub", func(u *model.User) string { return u.GitHub }, func(u *model.User, v string) { u.GitHub = v }, func(p *model.ParsedResume) string { return p.GitHub }},
+	{"portfolio", "portfolio", func(u *model.User) string { return u.Portfolio }, func(u *model.User, v string) { u.Portfolio = v }, func(p *model.ParsedResume) string { return p.Portfolio }},
+}
+
+func (s *ProfileService) GetUser(userID string) (*model.User, error) {
+	var user model.User
+	if err := s.DB.Preload("Education").Preload("Experience").First(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Preview diffs the user's profile against a parsed resume, section by section.
+func (s *ProfileService) Preview(user *model.User, parsed *model.ParsedResume) model.MergePreview {
+	preview := model.MergePreview{Basics: []model.FieldDiff{}}
+
+	for _, f := range profileFields {
+		current, next := f.get(user), strings.TrimSpace(f.from(parsed))
+		preview.Basics = append(preview.Basics, model.FieldDiff{
+			Field:   f.name,
+			Current: current,
+			Parsed:  next,
+			Changed: next != "" && !strings.EqualFold(strings.TrimSpace(current), next),
+		})
+	}
+
+	// Compared by canonical name, so "golang" on the profile matches a parsed "Go"
+	currentSkills, _ := skillTaxonomy.canonicalize(splitSkills(user.Skills))
+	parsedSkills, _ := skillTaxonomy.canonicalize(parsed.Skills)
+	preview.Skills = model.SkillsDiff{
+		Current: currentSkills,
+		Parsed:  parsedSkills,
+		Added:   diffFold(parsedSkills, currentSkills),
+		Missing: diffFold(currentSkills, parsedSkills),
+	}
+
+	preview.Education = model.EducationDiff{Current: user.Education, Parsed: parsed.Education, New: []model.ParsedEducation{}}
+	for _, e := range parsed.Education {
+		if !hasEducation(user.Education, e) {
+			preview.Education.New = append(preview.Education.New, e)
+		}
+	}
+
+	preview.Experience = model.ExperienceDiff{Current: user.Experience, Parsed: parsed.Experience, New: []model.ParsedExperience{}}
+	for _, e := range parsed.Experience {
+		if !hasExperience(user.Experience, e) {
+			preview.Experience.New = append(preview.Experience.New, e)
+		}
+	}
+
+	return preview
+}
+
+// Apply saves the parsed resume onto the profile using the chosen strategy
+// for each section, in a single transaction. A language, if given, is
+// recorded as the profile's; it must be one DetectLanguage knows.
+func (s *ProfileService) Apply(userID string, parsed *model.ParsedResume, language string, choices model.MergeChoices) (*model.User, error) {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if _, ok := utils.LanguageNames[language]; language != "" && !ok {
+		codes := make([]string, 0, len(utils.LanguageNames))
+		for code := range utils.LanguageNames {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		return nil, fmt.Errorf("%w %q: use one of %s", ErrUnknownLanguage, language, strings.Join(codes, ", "))
+	}
+	for _, c := range []*string{&choices.Basics, &choices.Skills, &choices.Education, &choices.Experience} {
+		if *c == "" {
+			*c = model.MergeStrategySkip
+		}
+		if *c != model.MergeStrategyMerge && *c != model.MergeStrategyReplace && *c != model.MergeStrategySkip {
+			return nil, ErrInvalidMergeChoice
+		}
+	}
+
+	// Unknown skills are reported outside the transaction
+	if choices.Skills != model.MergeStrategySkip {
+		parsed.Skills = skillTaxonomy.Normalize(parsed.Skills)
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		if err := tx.Preload("Education").Preload("Experience").First(&user, "id = ?", userID).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{}
+		if language != "" {
+			updates["language"] = language
+		}
+
+		if choices.Basics != model.MergeStrategySkip {
+			for _, f := range profileFields {
+				next := strings.TrimSpace(f.from(parsed))
+				if next == "" {
+					continue
+				}
+				if choices.Basics == model.MergeStrategyMerge && strings.TrimSpace(f.get(&user)) != "" {
+					continue
+				}
+				f.set(&user, next)
+				updates[f.column] = next
+			}
+			if _, ok := updates["location"]; ok {
+				user.Geo = utils.ResolveLocation(user.Location)
+				for k, v := range geoColumns(user.Geo) {
+					updates[k] = v
+				}
+			}
+		}
+
+		switch choices.Skills {
+		case model.MergeStrategyMerge:
+			merged, _ := skillTaxonomy.canonicalize(append(splitSkills(user.Skills), parsed.Skills...))
+			updates["skills"] = strings.Join(merged, ", ")
+		case model.MergeStrategyReplace:
+			updates["skills"] = strings.Join(parsed.Skills, ", ")
+		}
+
+		if len(updates) > 0 {
+			if err := tx.Model(&model.User{}).Where("id = ?", user.ID).Updates(updates).Error; err != nil {
+				return fmt.Errorf("update profile: %w", err)
+			}
+		}
+
+		if choices.Education == model.MergeStrategyReplace {
+			if err := tx.Where("user_id = ?", user.ID).Delete(&model.Education{}).Error; err != nil {
+				return fmt.Errorf("clear education: %w", err)
+			}
+			user.Education = nil
+		}
+		if choices.Education != model.MergeStrategySkip {
+			for _, e := range parsed.Education {
+				if choices.Education == model.MergeStrategyMerge && hasEducation(user.Education, e) {
+					continue
+				}
+				edu := model.Education{UserID: user.ID, Institution: e.Institution, Location: e.Location, Degree: e.Degree, GPA: e.GPA, Years: e.Years, Period: utils.ParseDateRange(e.Years)}
+				if err := tx.Create(&edu).Error; err != nil {
+					return fmt.Errorf("add education: %w", err)
+				}
+			}
+		}
+
+		if choices.Experience == model.MergeStrategyReplace {
+			if err := tx.Where("user_id = ?", user.ID).Delete(&model.Experience{}).Error; err != nil {
+				return fmt.Errorf("clear experience: %w", err)
+			}
+			user.Experience = nil
+		}
+		if choices.Experience != model.MergeStrategySkip {
+			for _, e := range parsed.Experience {
+				if choices.Experience == model.MergeStrategyMerge && hasExperience(user.Experience, e) {
+					continue
+				}
+				exp := model.Experience{UserID: user.ID, Company: e.Company, Location: e.Location, Title: e.Title, Years: e.Years, Period: utils.ParseDateRange(e.Years), Description: e.Description}
+				if err := tx.Create(&exp).Error; err != nil {
+					return fmt.Errorf("add experience: %w", err)
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetUser(userID)
+}
+
+// splitSkills turns the comma-separated User.Skills into a list.
+func splitSkills(skills string) []string {
+	var out []string
+	for _, s := range strings.Split(skills, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// diffFold returns the items of a missing from b, compared case-insensitively.
+func diffFold(a, b []string) []string {
+	out := []string{}
+	for _, item := range a {
+		if !containsFold(b, item) && !containsFold(out, item) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func hasEducation(list []model.Education, e model.ParsedEducation) bool {
+	for _, cur := range list {
+		if sameText(cur.Institution, e.Institution) && sameText(cur.Degree, e.Degree) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasExperience(list []model.Experience, e model.ParsedExperience) bool {
+	for _, cur := range list {
+		if sameText(cur.Company, e.Company) && sameText(cur.Title, e.Title) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameText(a, b string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(a), " "), strings.Join(strings.Fields(b), " "))
+}
+
+// BackfillPeriods normalizes the years of education and experience entries
+// that were saved before periods were.
+func (s *ProfileService) BackfillPeriods() error {
+	var education []model.Education
+	if err := s.DB.Where("years <> '' AND (period_precision IS NULL OR period_precision = '')").Find(&education).Error; err != nil {
+		return err
+	}
+	for _, e := range education {
+		if err := s.DB.Model(&model.Education{}).Where("id = ?", e.ID).Updates(periodColumns(utils.ParseDateRange(e.Years))).Error; err != nil {
+			return err
+		}
+	}
+
+	var experience []model.Experience
+	if err := s.DB.Where("years <> '' AND (period_precision IS NULL OR period_precision = '')").Find(&experience).Error; err != nil {
+		return err
+	}
+	for _, e := range experience {
+		if err := s.DB.Model(&model.Experience{}).Where("id = ?", e.ID).Updates(periodColumns(utils.ParseDateRange(e.Years))).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func periodColumns(period model.DateRange) map[string]interface{} {
+	return map[string]interface{}{
+		"period_start":           period.Start,
+		"period_end":             period.End,
+		"period_precision":       period.Precision,
+		"period_ongoing":         period.Ongoing,
+		"period_duration_months": period.DurationMonths,
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrReferralNotFound  = errors.New("referral code not found")
+	ErrSelfReferral      = errors.New("you cannot use your own referral code")
+	ErrReferralMismatch  = errors.New("referral code is for a different job")
+	ErrRecruiterReferral = errors.New("a job's recruiter cannot refer candidates to it")
+)
+
+type ReferralService struct {
+	DB *gorm.DB
+
+	RewardStage   string // pipeline stage that triggers the reward
+	RewardCredits int
+}
+
+// GetOrCreate returns the user's referral code for a job, creating it on first
+// use. The job's own recruiter gets ErrRecruiterReferral.
+func (s *ReferralService) GetOrCreate(referrerID uuid.UUID, jobID uint) (*model.Referral, error) {
+	if err := checkReferrer(s.DB, referrerID, jobID); err != nil {
+		return nil, err
+	}
+
+	var ref model.Referral
+	err := s.DB.Where("referrer_id = ? AND job_id = ?", referrerID, jobID).First(&ref).Error
+	if err == nil {
+		return &ref, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	code, err := newReferralCode()
+	if err != nil {
+		return nil, err
+	}
+	ref = model.Referral{Code: code, ReferrerID: referrerID, JobID: jobID}
+	if err := s.DB.Create(&ref).Error; err != nil {
+		// A concurrent request may have created the code first
+		var existing model.Referral
+		if s.DB.Where("referrer_id = ? AND job_id = ?", referrerID, jobID).First(&existing).Error == nil {
+			return &existing, nil
+		}
+		return nil, err
+	}
+	return &ref, nil
+}
+
+func (s *ReferralService) GetByCode(code string) (*model.Referral, error) {
+	var ref model.Referral
+	err := s.DB.Preload("Job").Where("code = ?", strings.ToUpper(strings.TrimSpace(code))).First(&ref).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrReferralNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ref, nil
+}
+
+// RecordClick attributes a visit to the referral.
+func (s *ReferralService) RecordClick(ref *model.Referral, visitorID string) error {
+	click := model.ReferralClick{ReferralID: ref.ID, VisitorID: visitorID}
+	return s.DB.Create(&click).Error
+}
+
+// resolveForApplication validates a referral code used when applying.
+func (s *ReferralService) resolveForApplication(tx *gorm.DB, code string, jobID uint, applicantID uuid.UUID) (*model.Referral, error) {
+	var ref model.Referral
+	err := tx.Where("code = ?", strings.ToUpper(strings.TrimSpace(code))).First(&ref).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrReferralNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ref.JobID != jobID {
+		return nil, ErrReferralMismatch
+	}
+	if ref.ReferrerID == applicantID {
+		return nil, ErrSelfReferral
+	}
+	// Codes created before recruiters were refused may still be around
+	if err := checkReferrer(tx, ref.ReferrerID, jobID); err != nil {
+		return nil, err
+	}
+	return &ref, nil
+}
+
+// checkReferrer refuses the job's recruiter as a referrer: the reward would
+// pay them for moving their own applicants through the pipeline.
+func checkReferrer(db *gorm.DB, referrerID uuid.UUID, jobID uint) error {
+	var job model.Job
+	if err := db.Select("recruiter_id").First(&job, jobID).Error; err != nil {
+		return err
+	}
+	if job.RecruiterID == referrerID {
+		return ErrRecruiterReferral
+	}
+	return nil
+}
+
+// grantReward credits the referrer once the referred application reaches the
+// configured stage. It is idempotent per application.
+func (s *ReferralService) grantReward(tx *gorm.DB, app *model.JobApplication) error {
+	if app.ReferralID == nil || s.RewardCredits <= 0 {
+		return nil
+	}
+	if model.StageRank(app.Stage) < 0 || model.StageRank(app.Stage) < model.StageRank(s.RewardStage) {
+		return nil
+	}
+
+	var ref model.Referral
+	if err := tx.First(&ref, *app.ReferralID).Error; err != nil {
+		return err
+	}
+
+	var existing int64
+	if err := tx.Model(&model.ReferralReward{}).Where("application_id = ?", app.ID).Count(&existing).Error; err != nil {
+		return err
+	}
+	if existing > 0 {
+		return nil
+	}
+
+	reward := model.ReferralReward{
+		ReferralID:    ref.ID,
+		ApplicationID: app.ID,
+		ReferrerID:    ref.ReferrerID,
+		Stage:         app.Stage,
+		Credits:       s.RewardCredits,
+	}
+	if err := tx.Create(&reward).Error; err != nil {
+		return err
+	}
+	return tx.Model(&model.User{}).Where("id = ?", ref.ReferrerID).
+		Update("credits", gorm.Expr("credits + ?", s.RewardCredits)).Error
+}
+
+// Dashboard summarizes clicks, applications and rewards for each of the user's referral codes.
+func (s *ReferralService) Dashboard(userID uuid.UUID) (*model.ReferralDashboard, error) {
+	var refs []model.Referral
+	if err := s.DB.Preload("Job").Where("referrer_id = ?", userID).Order("created_at desc").Find(&refs).Error; err != nil {
+		return nil, err
+	}
+
+	dash := &model.ReferralDashboard{UserID: userID, Referrals: []model.ReferralStats{}}
+	for _, ref := range refs {
+		stats := model.ReferralStats{Code: ref.Code, JobID: ref.JobID, JobTitle: ref.Job.Title}
+
+		if err := s.DB.Model(&model.ReferralClick{}).Where("referral_id = ?", ref.ID).Count(&stats.Clicks).Error; err != nil {
+			return nil, err
+		}
+		if err := s.DB.Model(&model.ReferralClick{}).Where("referral_id = ?", ref.ID).
+			Distinct("visitor_id").Count(&stats.UniqueVisitors).Error; err != nil {
+			return nil, err
+		}
+		if err := s.DB.Model(&model.JobApplication{}).Where("referral_id = ?", ref.ID).Count(&stats.Applications).Error; err != nil {
+			return nil, err
+		}
+		if err := s.DB.Model(&model.ReferralReward{}).Where("referral_id = ?", ref.ID).
+			Select("COUNT(*) AS rewarded, COALESCE(SUM(credits), 0) AS credits_earned").
+			Row().Scan(&stats.Rewarded, &stats.CreditsEarned); err != nil {
+			return nil, err
+		}
+
+		dash.Clicks += stats.Clicks
+		dash.Applications += stats.Applications
+		dash.Rewarded += stats.Rewarded
+		dash.CreditsEarned += stats.CreditsEarned
+		dash.Referrals = append(dash.Referrals, stats)
+	}
+	return dash, nil
+}
+
+func newReferralCode() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.EncodeToString(b), nil // 8 characters, A-Z2-7
+}
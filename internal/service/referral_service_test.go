@@ -0,0 +1,79 @@
+package service
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB opens an empty SQLite database with the job and referral tables.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&model.User{}, &model.Job{}, &model.JobApplication{}, &model.ScreeningQuestion{},
+		&model.ApplicationAnswer{}, &model.Referral{}, &model.ReferralClick{}, &model.ReferralReward{}, &model.Resume{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// createUser stores a user with the given email and role.
+func createUser(t *testing.T, db *gorm.DB, email, role string) model.User {
+	t.Helper()
+	user := model.User{Email: email, Role: role}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func TestReferralRecruiterCannotReferToOwnJob(t *testing.T) {
+	db := newTestDB(t)
+	recruiter := createUser(t, db, "recruiter@example.com", "recruiter")
+	member := createUser(t, db, "member@example.com", "applicant")
+	job := model.Job{Title: "Backend Engineer", RecruiterID: recruiter.ID}
+	if err := db.Create(&job).Error; err != nil {
+		t.Fatal(err)
+	}
+	s := &ReferralService{DB: db}
+
+	if _, err := s.GetOrCreate(recruiter.ID, job.ID); !errors.Is(err, ErrRecruiterReferral) {
+		t.Errorf("the recruiter got a referral code for their own job: %v", err)
+	}
+	if _, err := s.GetOrCreate(member.ID, job.ID); err != nil {
+		t.Errorf("a community member could not get a referral code: %v", err)
+	}
+}
+
+func TestApplyRejectsRecruiterReferralCode(t *testing.T) {
+	db := newTestDB(t)
+	recruiter := createUser(t, db, "recruiter@example.com", "recruiter")
+	applicant := createUser(t, db, "applicant@example.com", "applicant")
+	job := model.Job{Title: "Backend Engineer", RecruiterID: recruiter.ID}
+	if err := db.Create(&job).Error; err != nil {
+		t.Fatal(err)
+	}
+	// A code the recruiter created before they were refused
+	ref := model.Referral{Code: "RECRUITER1", ReferrerID: recruiter.ID, JobID: job.ID}
+	if err := db.Create(&ref).Error; err != nil {
+		t.Fatal(err)
+	}
+	s := &ApplicationService{DB: db, Referrals: &ReferralService{DB: db, RewardStage: model.StageHired, RewardCredits: 10}}
+
+	if _, err := s.Apply(job.ID, applicant.ID, ApplyInput{ReferralCode: ref.Code}); !errors.Is(err, ErrRecruiterReferral) {
+		t.Fatalf("Apply with the recruiter's code = %v, want ErrRecruiterReferral", err)
+	}
+	var count int64
+	db.Model(&model.JobApplication{}).Count(&count)
+	if count != 0 {
+		t.Errorf("the rejected application was stored")
+	}
+}
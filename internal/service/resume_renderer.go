@@ -0,0 +1,394 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrUnknownTemplate    = errors.New("unknown resume template")
+	ErrUnsupportedPDFText = errors.New("the PDF fonts cannot show some characters of the profile")
+)
+
+// Resume render formats
+const (
+	ResumeFormatPDF  = "pdf"
+	ResumeFormatHTML = "html"
+)
+
+// resumeRendererVersion is part of every cached render's hash; bump it when
+// the layout changes so cached resumes are rendered again.
+const resumeRendererVersion = "2"
+
+// DefaultResumeTemplate is used when no template is asked for
+const DefaultResumeTemplate = "classic"
+
+// ResumeTemplate is a layout for rendered resumes. All of them are single
+// column with plain section headings, which ATS parsers read reliably.
+type ResumeTemplate struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	font, boldFont                  string
+	nameSize, headingSize, bodySize float64
+	margin                          float64
+	accent                          [3]float64 // RGB 0-1 of headings and rules
+	centered                        bool       // center the name and contact line
+	cssFont                         string
+}
+
+// ResumeTemplates are the available templates, the default first.
+var ResumeTemplates = []*ResumeTemplate{
+	{
+		Name: "classic", Description: "Serif, centered header, black and white",
+		font: utils.FontTimes, boldFont: utils.FontTimesBold,
+		nameSize: 22, headingSize: 12, bodySize: 10.5, margin: 54,
+		centered: true, cssFont: `"Times New Roman", Times, serif`,
+	},
+	{
+		Name: "modern", Description: "Sans-serif with blue headings and rules",
+		font: utils.FontHelvetica, boldFont: utils.FontHelveticaBold,
+		nameSize: 24, headingSize: 11.5, bodySize: 10, margin: 50,
+		accent: [3]float64{0.11, 0.33, 0.62}, cssFont: `Helvetica, Arial, sans-serif`,
+	},
+	{
+		Name: "compact", Description: "Small sans-serif type and margins, to fit more on a page",
+		font: utils.FontHelvetica, boldFont: utils.FontHelveticaBold,
+		nameSize: 17, headingSize: 10, bodySize: 8.5, margin: 36,
+		cssFont: `Helvetica, Arial, sans-serif`,
+	},
+}
+
+// FindResumeTemplate returns the template called name.
+func FindResumeTemplate(name string) (*ResumeTemplate, error) {
+	for _, t := range ResumeTemplates {
+		if strings.EqualFold(t.Name, name) {
+			return t, nil
+		}
+	}
+	names := make([]string, len(ResumeTemplates))
+	for i, t := range ResumeTemplates {
+		names[i] = t.Name
+	}
+	return nil, fmt.Errorf("%w %q: use one of %s", ErrUnknownTemplate, name, strings.Join(names, ", "))
+}
+
+// ResumeRenderService renders profiles as formatted resumes. Renders are
+// stored per user, template and format, and reused until the profile changes.
+type ResumeRenderService struct {
+	DB *gorm.DB
+}
+
+// Render returns the user's resume in template and format ("pdf" or "html"),
+// from the cache when the profile has not changed since it was last rendered.
+func (s *ResumeRenderService) Render(user *model.User, tmpl *ResumeTemplate, format string) (*model.ResumeRender, error) {
+	view := buildResumeView(user)
+	if format == ResumeFormatPDF {
+		// The PDF's standard fonts only cover Western European text
+		if chars := utils.UnsupportedPDFChars(view.text()); len(chars) > 0 {
+			return nil, fmt.Errorf("%w: %q; use the HTML preview instead", ErrUnsupportedPDFText, string(chars))
+		}
+	}
+	hash, err := resumeViewHash(view, tmpl.Name, format)
+	if err != nil {
+		return nil, err
+	}
+
+	var cached model.ResumeRender
+	err = s.DB.First(&cached, "user_id = ? AND template = ? AND format = ?", user.ID, tmpl.Name, format).Error
+	if err == nil && cached.ProfileHash == hash {
+		return &cached, nil
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	render := &model.ResumeRender{UserID: user.ID, Template: tmpl.Name, Format: format, ProfileHash: hash, CreatedAt: time.Now()}
+	switch format {
+	case ResumeFormatPDF:
+		render.Content = renderResumePDF(view, tmpl)
+	case ResumeFormatHTML:
+		if render.Content, err = renderResumeHTML(view, tmpl); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown resume format %q", format)
+	}
+
+	if err := s.DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(render).Error; err != nil {
+		return nil, err
+	}
+	return render, nil
+}
+
+func resumeViewHash(view resumeView, template, format string) (string, error) {
+	data, err := json.Marshal(view)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append([]byte(resumeRendererVersion+"\x00"+template+"\x00"+format+"\x00"), data...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// resumeView is what a rendered resume shows. Its hash decides whether a
+// cached render is still current, so it holds nothing that is not shown.
+type resumeView struct {
+	Name       string
+	Title      string
+	Contact    []string
+	Summary    string
+	Experience []resumeViewEntry
+	Education  []resumeViewEntry
+	Skills     []string
+}
+
+type resumeViewEntry struct {
+	Heading    string
+	Subheading string
+	Dates      string
+	Bullets    []string
+}
+
+// text joins everything the view shows.
+func (v resumeView) text() string {
+	parts := append([]string{v.Name, v.Title, v.Summary}, v.Contact...)
+	for _, e := range append(v.Experience, v.Education...) {
+		parts = append(append(parts, e.Heading, e.Subheading, e.Dates), e.Bullets...)
+	}
+	return strings.Join(append(parts, v.Skills...), "\n")
+}
+
+func buildResumeView(user *model.User) resumeView {
+	view := resumeView{Name: user.FullName, Title: user.Title, Skills: splitSkills(user.Skills)}
+	for _, v := range []string{user.Email, user.Phone, user.Location, user.LinkedIn, user.GitHub, user.Portfolio} {
+		if v = strings.TrimSpace(v); v != "" {
+			view.Contact = append(view.Contact, v)
+		}
+	}
+	if basics, _ := user.ResumeExtensions["basics"].(map[string]interface{}); basics != nil {
+		view.Summary = strings.TrimSpace(jsonString(basics, "summary"))
+	}
+
+	experience := append([]model.Experience(nil), user.Experience...)
+	sort.SliceStable(experience, func(i, j int) bool { return newerPeriod(experience[i].Period, experience[j].Period) })
+	for _, e := range experience {
+		entry := resumeViewEntry{Heading: e.Title, Subheading: joinNonEmpty(", ", e.Company, e.Location), Dates: e.Years,
+			Bullets: descriptionBullets(e.Description)}
+		if entry.Heading == "" {
+			entry.Heading, entry.Subheading = entry.Subheading, ""
+		}
+		view.Experience = append(view.Experience, entry)
+	}
+
+	education := append([]model.Education(nil), user.Education...)
+	sort.SliceStable(education, func(i, j int) bool { return newerPeriod(education[i].Period, education[j].Period) })
+	for _, e := range education {
+		entry := resumeViewEntry{Heading: e.Degree, Subheading: joinNonEmpty(", ", e.Institution, e.Location), Dates: e.Years}
+		if entry.Heading == "" {
+			entry.Heading, entry.Subheading = entry.Subheading, ""
+		}
+		if e.GPA != "" {
+			entry.Subheading = joinNonEmpty(" | ", entry.Subheading, "GPA "+e.GPA)
+		}
+		view.Education = append(view.Education, entry)
+	}
+	return view
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	var out []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, sep)
+}
+
+// descriptionBullets splits a description into one bullet per line, without
+// the bullet characters it was written with.
+func descriptionBullets(description string) []string {
+	var bullets []string
+	for _, line := range strings.Split(description, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*•·▪"))
+		if line != "" {
+			bullets = append(bullets, line)
+		}
+	}
+	return bullets
+}
+
+// pdfLayout flows text down the pages of a PDF, starting a page when one is full.
+type pdfLayout struct {
+	pdf *utils.PDFWriter
+	t   *ResumeTemplate
+	y   float64 // top of the next line
+}
+
+func (l *pdfLayout) width() float64 { return l.pdf.Width - 2*l.t.margin }
+
+// space starts a new page unless h more points fit on this one.
+func (l *pdfLayout) space(h float64) {
+	if l.y+h > l.pdf.Height-l.t.margin {
+		l.pdf.AddPage()
+		l.y = l.t.margin
+	}
+}
+
+// line writes one line of text at x; centered lines ignore x.
+func (l *pdfLayout) line(font string, size, x float64, centered bool, s string) {
+	leading := size * 1.3
+	l.space(leading)
+	if centered {
+		x = l.t.margin + (l.width()-utils.TextWidth(font, size, s))/2
+	}
+	l.pdf.Text(x, l.y+size, font, size, s)
+	l.y += leading
+}
+
+// paragraph writes s wrapped to the text width, indented by indent.
+func (l *pdfLayout) paragraph(font string, size, indent float64, centered bool, s string) {
+	for _, line := range wrapText(font, size, l.width()-indent, s) {
+		l.line(font, size, l.t.margin+indent, centered, line)
+	}
+}
+
+func (l *pdfLayout) heading(title string) {
+	t := l.t
+	l.y += t.bodySize * 0.9
+	l.space(t.headingSize*1.3 + t.bodySize*4) // keep the heading with the first lines of its section
+	l.pdf.SetColor(t.accent[0], t.accent[1], t.accent[2])
+	l.line(t.boldFont, t.headingSize, t.margin, false, strings.ToUpper(title))
+	l.pdf.Line(t.margin, l.y-1, t.margin+l.width(), l.y-1, 0.6)
+	l.pdf.SetColor(0, 0, 0)
+	l.y += t.bodySize * 0.3
+}
+
+func (l *pdfLayout) entry(e resumeViewEntry) {
+	t := l.t
+	l.space(t.bodySize * 1.3 * 2)
+
+	// The dates go on the right of the heading's first line
+	datesWidth := utils.TextWidth(t.font, t.bodySize, e.Dates)
+	headingWidth := l.width()
+	if e.Dates != "" {
+		headingWidth -= datesWidth + t.bodySize
+	}
+	for i, line := range wrapText(t.boldFont, t.bodySize, headingWidth, e.Heading) {
+		if i == 0 && e.Dates != "" {
+			l.space(t.bodySize * 1.3)
+			l.pdf.Text(t.margin+l.width()-datesWidth, l.y+t.bodySize, t.font, t.bodySize, e.Dates)
+		}
+		l.line(t.boldFont, t.bodySize, t.margin, false, line)
+	}
+	if e.Subheading != "" {
+		l.paragraph(t.font, t.bodySize, 0, false, e.Subheading)
+	}
+	indent := t.bodySize * 1.2
+	for _, bullet := range e.Bullets {
+		l.space(t.bodySize * 1.3)
+		l.pdf.Text(t.margin+indent*0.3, l.y+t.bodySize, t.font, t.bodySize, "•")
+		l.paragraph(t.font, t.bodySize, indent, false, bullet)
+	}
+	l.y += t.bodySize * 0.5
+}
+
+func renderResumePDF(view resumeView, t *ResumeTemplate) []byte {
+	pdf := utils.NewPDFWriter()
+	pdf.Title = strings.TrimSpace(view.Name + " - Resume")
+	pdf.AddPage()
+	l := &pdfLayout{pdf: pdf, t: t, y: t.margin}
+
+	if view.Name != "" {
+		l.paragraph(t.boldFont, t.nameSize, 0, t.centered, view.Name)
+	}
+	if view.Title != "" {
+		l.paragraph(t.font, t.bodySize*1.25, 0, t.centered, view.Title)
+	}
+	if len(view.Contact) > 0 {
+		l.paragraph(t.font, t.bodySize, 0, t.centered, strings.Join(view.Contact, "  |  "))
+	}
+
+	if view.Summary != "" {
+		l.heading("Summary")
+		l.paragraph(t.font, t.bodySize, 0, false, view.Summary)
+	}
+	if len(view.Experience) > 0 {
+		l.heading("Experience")
+		for _, e := range view.Experience {
+			l.entry(e)
+		}
+	}
+	if len(view.Education) > 0 {
+		l.heading("Education")
+		for _, e := range view.Education {
+			l.entry(e)
+		}
+	}
+	if len(view.Skills) > 0 {
+		l.heading("Skills")
+		l.paragraph(t.font, t.bodySize, 0, false, strings.Join(view.Skills, ", "))
+	}
+	return pdf.Bytes()
+}
+
+// wrapText breaks s into lines no wider than width. A word wider than a
+// whole line gets a line of its own.
+func wrapText(font string, size, width float64, s string) []string {
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if current != "" && utils.TextWidth(font, size, candidate) > width {
+			lines = append(lines, current)
+			candidate = word
+		}
+		current = candidate
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+//go:embed templates/resume.html
+var resumeHTMLSource string
+
+var resumeHTMLTemplate = template.Must(template.New("resume").Parse(resumeHTMLSource))
+
+func renderResumeHTML(view resumeView, t *ResumeTemplate) ([]byte, error) {
+	align := "left"
+	if t.centered {
+		align = "center"
+	}
+	var buf bytes.Buffer
+	err := resumeHTMLTemplate.Execute(&buf, map[string]interface{}{
+		"View":     view,
+		"Template": t.Name,
+		"Font":     template.CSS(t.cssFont),
+		"Accent":   template.CSS(fmt.Sprintf("#%02x%02x%02x", int(t.accent[0]*255), int(t.accent[1]*255), int(t.accent[2]*255))),
+		"NameSize": t.nameSize,
+		"HeadSize": t.headingSize,
+		"BodySize": t.bodySize,
+		"Margin":   t.margin,
+		"Align":    align,
+	})
+	return buf.Bytes(), err
+}
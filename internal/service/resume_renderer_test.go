@@ -0,0 +1,66 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/utils"
+)
+
+func TestRenderResumePDFText(t *testing.T) {
+	user := &model.User{
+		FullName: "José Müller",
+		Title:    "Développeur Backend",
+		Email:    "jose@example.com",
+		Location: "Zürich",
+		Skills:   "Go, PostgreSQL, Kubernetes",
+		Experience: []model.Experience{{
+			Title: "Backend Engineer", Company: "Ærø Systems", Location: "København", Years: "2019 - 2023",
+			Description: "- Built the payment API in Go\n- Cut latency by 40% with caching",
+		}},
+		Education: []model.Education{{Degree: "MSc Informatik", Institution: "ETH Zürich", Years: "2017 - 2019"}},
+	}
+
+	for _, tmpl := range ResumeTemplates {
+		t.Run(tmpl.Name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "resume.pdf")
+			if err := os.WriteFile(path, renderResumePDF(buildResumeView(user), tmpl), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			doc, err := utils.ExtractPDF(path)
+			if err != nil {
+				t.Fatalf("the rendered PDF does not parse: %v", err)
+			}
+			for _, want := range []string{
+				"José Müller", "Développeur Backend", "jose@example.com", "Zürich",
+				"Backend Engineer", "Ærø Systems, København", "2019 - 2023",
+				"Built the payment API in Go", "Cut latency by 40% with caching",
+				"MSc Informatik", "ETH Zürich", "Go, PostgreSQL, Kubernetes",
+			} {
+				if !strings.Contains(doc.Text, want) {
+					t.Errorf("%q is not in the rendered PDF:\n%s", want, doc.Text)
+				}
+			}
+			if strings.Contains(doc.Text, "?") {
+				t.Errorf("the rendered PDF has replacement characters:\n%s", doc.Text)
+			}
+		})
+	}
+}
+
+func TestRenderRejectsTextThePDFFontsCannotShow(t *testing.T) {
+	s := &ResumeRenderService{} // refused before the cache is read
+	user := &model.User{FullName: "Анна Петрова", Skills: "Go"}
+
+	_, err := s.Render(user, ResumeTemplates[0], ResumeFormatPDF)
+	if !errors.Is(err, ErrUnsupportedPDFText) {
+		t.Fatalf("Render() error = %v, want ErrUnsupportedPDFText", err)
+	}
+	if !strings.Contains(err.Error(), "А") {
+		t.Errorf("the error does not name the characters: %v", err)
+	}
+}
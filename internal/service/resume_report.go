@@ -0,0 +1,610 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/utils"
+	"gorm.io/gorm"
+)
+
+var ErrTargetJobNotFound = errors.New("target job not found")
+
+const (
+	maxTargetJobs     = 10 // jobs compared in one report
+	maxBulletRewrites = 10 // bullets sent to Gemini in one report
+	minGapMonths      = 6  // shorter breaks between positions are not reported
+
+	// Words a resume should have: fewer reads as thin, more as padded
+	minResumeWords, maxResumeWords = 300, 1000
+	maxResumePages                 = 2
+
+	// keywordStuffing is a density (mentions per 100 words) of one keyword
+	// that reads as stuffed rather than written, once it is mentioned at
+	// least minStuffedMentions times
+	keywordStuffing    = 3.0
+	minStuffedMentions = 6
+)
+
+// reportWeights is how much each check counts towards the overall score.
+var reportWeights = map[string]int{
+	model.ReportCheckSections: 20,
+	model.ReportCheckContact:  15,
+	model.ReportCheckBullets:  20,
+	model.ReportCheckDates:    10,
+	model.ReportCheckKeywords: 25,
+	model.ReportCheckLength:   10,
+}
+
+// quantityPattern finds figures in a bullet: numbers, percentages, amounts
+// and number words ("doubled", "dozens").
+var quantityPattern = regexp.MustCompile(`(?i)\d|%|[$€£₹¥]|\b(one|two|three|four|five|six|seven|eight|nine|ten|twice|double[ds]?|tripled?|dozens?|hundreds?|thousands?|millions?|billions?)\b`)
+
+// ReportOptions picks what a resume report covers.
+type ReportOptions struct {
+	ResumeID *uuid.UUID // the version to analyze; the primary one when nil
+	JobIDs   []uint     // target jobs; the jobs applied to most recently when empty
+	Rewrite  bool       // ask Gemini to rewrite unquantified bullets
+}
+
+// ResumeReportService scores resumes against applicant tracking system
+// checks. Every check runs locally; only the optional rewrites use Gemini.
+type ResumeReportService struct {
+	DB      *gorm.DB
+	Resumes *ResumeService
+}
+
+// reportSubject is the resume a report is about.
+type reportSubject struct {
+	resumeID *uuid.UUID // the stored version, nil for the profile
+	resume   *model.ParsedResume
+	text     string
+	pages    int
+}
+
+// Report analyzes a user's stored resume, or their profile if they have
+// not stored one. The profile also points out what the resume leaves out.
+func (s *ResumeReportService) Report(user *model.User, opts ReportOptions) (*model.ResumeReport, error) {
+	report := &model.ResumeReport{
+		UserID:              user.ID,
+		Source:              model.ReportSourceProfile,
+		Suggestions:         []string{},
+		TargetJobs:          []uint{},
+		Keywords:            []model.KeywordMatch{},
+		UnquantifiedBullets: []model.ResumeBullet{},
+		Gaps:                []model.EmploymentGap{},
+		GeneratedAt:         time.Now(),
+	}
+
+	profile := profileAsResume(user)
+	subject, err := s.subject(user, opts.ResumeID)
+	if err != nil {
+		return nil, err
+	}
+	if subject == nil {
+		subject = &reportSubject{resume: profile, text: profileText(user)}
+	} else {
+		report.Source, report.ResumeID = model.ReportSourceResume, subject.resumeID
+	}
+	jobs, err := s.targetJobs(user.ID, opts.JobIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		report.TargetJobs = append(report.TargetJobs, job.ID)
+	}
+
+	report.WordCount = len(strings.Fields(subject.text))
+	report.PageCount = subject.pages
+	report.Checks = []model.ReportCheck{
+		checkSections(subject.resume, profile),
+		checkContact(subject.resume, profile),
+		checkBullets(subject.resume, report),
+		checkDates(subject.resume, report, report.GeneratedAt),
+		checkKeywords(subject, jobs, report),
+		checkLength(report),
+	}
+	scoreReport(report)
+
+	if opts.Rewrite {
+		report.Rewrites, err = rewriteBullets(report.UnquantifiedBullets)
+		if err != nil {
+			log.Printf("⚠️ Bullet rewrites failed: %v", err)
+			report.RewriteError = "rewrites are unavailable: " + err.Error()
+		}
+	}
+	return report, nil
+}
+
+// subject loads the stored resume version to analyze, or returns nil when
+// the user has none and the profile stands in for it.
+func (s *ResumeReportService) subject(user *model.User, resumeID *uuid.UUID) (*reportSubject, error) {
+	var stored *model.Resume
+	var err error
+	if resumeID != nil {
+		stored, err = s.Resumes.Get(user.ID, *resumeID)
+	} else {
+		stored, err = s.Resumes.Primary(user.ID)
+		if errors.Is(err, ErrResumeNotFound) {
+			return nil, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stored.Parsed == nil || stored.Parsed.Resume == nil {
+		return nil, fmt.Errorf("resume version %d was stored without its parse", stored.Version)
+	}
+
+	doc, err := s.Resumes.Document(stored)
+	if err != nil {
+		return nil, err
+	}
+	return &reportSubject{resumeID: &stored.ID, resume: stored.Parsed.Resume, text: doc.Text, pages: len(doc.Pages)}, nil
+}
+
+// targetJobs loads the jobs keywords are compared with: the ones asked for,
+// or those the user applied to most recently.
+func (s *ResumeReportService) targetJobs(userID uuid.UUID, ids []uint) ([]model.Job, error) {
+	var jobs []model.Job
+	if len(ids) == 0 {
+		err := s.DB.Joins("JOIN job_applications ON job_applications.job_id = jobs.id").
+			Where("job_applications.user_id = ?", userID).
+			Order("job_applications.created_at desc").Limit(maxTargetJobs).Find(&jobs).Error
+		return jobs, err
+	}
+
+	if len(ids) > maxTargetJobs {
+		ids = ids[:maxTargetJobs]
+	}
+	if err := s.DB.Where("id IN ?", ids).Order("id").Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if !containsJob(jobs, id) {
+			return nil, fmt.Errorf("%w: %d", ErrTargetJobNotFound, id)
+		}
+	}
+	return jobs, nil
+}
+
+func containsJob(jobs []model.Job, id uint) bool {
+	for _, job := range jobs {
+		if job.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// profileAsResume reads a profile the way a parsed resume is read.
+func profileAsResume(user *model.User) *model.ParsedResume {
+	resume := &model.ParsedResume{
+		FullName: user.FullName, Title: user.Title, Location: user.Location, Email: user.Email, Phone: user.Phone,
+		CurrentCompany: user.CurrentCompany, LinkedIn: user.LinkedIn, GitHub: user.GitHub, Portfolio: user.Portfolio,
+		Skills: splitSkills(user.Skills),
+	}
+	for _, e := range user.Experience {
+		period := e.Period
+		resume.Experience = append(resume.Experience, model.ParsedExperience{
+			Company: e.Company, Location: e.Location, Title: e.Title, Years: e.Years, Period: &period, Description: e.Description,
+		})
+	}
+	for _, e := range user.Education {
+		period := e.Period
+		resume.Education = append(resume.Education, model.ParsedEducation{
+			Institution: e.Institution, Location: e.Location, Degree: e.Degree, GPA: e.GPA, Years: e.Years, Period: &period,
+		})
+	}
+	return resume
+}
+
+// profileText is the text of the profile rendered as a resume.
+func profileText(user *model.User) string {
+	view := buildResumeView(user)
+	lines := []string{view.Name, view.Title, strings.Join(view.Contact, " | "), view.Summary}
+	for _, e := range append(view.Experience, view.Education...) {
+		lines = append(lines, e.Heading, e.Subheading, e.Dates)
+		lines = append(lines, e.Bullets...)
+	}
+	lines = append(lines, strings.Join(view.Skills, ", "))
+	return strings.Join(lines, "\n")
+}
+
+// checkSections looks for the sections every resume needs.
+func checkSections(resume, profile *model.ParsedResume) model.ReportCheck {
+	check := model.ReportCheck{Name: model.ReportCheckSections}
+	sections := []struct {
+		name           string
+		has, onProfile bool
+		advice         string
+	}{
+		{"headline", strings.TrimSpace(resume.Title) != "", profile.Title != "",
+			"Add a headline under your name with the role you are after, e.g. \"Backend Engineer\""},
+		{"experience", len(resume.Experience) > 0, len(profile.Experience) > 0,
+			"Add an Experience section listing your positions, newest first, with company, title and dates"},
+		{"education", len(resume.Education) > 0, len(profile.Education) > 0,
+			"Add an Education section with your degree, institution and graduation year"},
+		{"skills", len(resume.Skills) > 0, len(profile.Skills) > 0,
+			"Add a Skills section listing the tools and technologies you use, as plain comma-separated words"},
+	}
+
+	var missing []string
+	for _, sec := range sections {
+		if sec.has {
+			continue
+		}
+		missing = append(missing, sec.name)
+		advice := sec.advice
+		if sec.onProfile {
+			advice += " (your profile already has one)"
+		}
+		check.Suggestions = append(check.Suggestions, advice)
+	}
+	check.Score = percent(len(sections)-len(missing), len(sections))
+	check.Message = "All standard sections are present"
+	if len(missing) > 0 {
+		check.Message = "Missing: " + strings.Join(missing, ", ")
+	}
+	return finishCheck(check)
+}
+
+// checkContact checks the ways a recruiter can reach and look up the
+// candidate, weighted by how much they are expected.
+func checkContact(resume, profile *model.ParsedResume) model.ReportCheck {
+	check := model.ReportCheck{Name: model.ReportCheckContact}
+	fields := []struct {
+		name             string
+		value, onProfile string
+		weight           int
+	}{
+		{"email", resume.Email, profile.Email, 30},
+		{"phone", resume.Phone, profile.Phone, 25},
+		{"location", resume.Location, profile.Location, 20},
+		{"LinkedIn", resume.LinkedIn, profile.LinkedIn, 15},
+		{"GitHub or portfolio", resume.GitHub + resume.Portfolio, profile.GitHub + profile.Portfolio, 10},
+	}
+
+	var missing []string
+	for _, f := range fields {
+		if strings.TrimSpace(f.value) != "" {
+			check.Score += f.weight
+			continue
+		}
+		missing = append(missing, f.name)
+		advice := "Add your " + f.name + " to the header"
+		if f.onProfile != "" {
+			advice += " (it is on your profile)"
+		}
+		check.Suggestions = append(check.Suggestions, advice)
+	}
+	check.Message = "Contact details are complete"
+	if len(missing) > 0 {
+		check.Message = "Missing: " + strings.Join(missing, ", ")
+	}
+	return finishCheck(check)
+}
+
+// checkBullets looks for figures in every experience bullet: results with
+// numbers stand out to recruiters and match "measurable" screening.
+func checkBullets(resume *model.ParsedResume, report *model.ResumeReport) model.ReportCheck {
+	check := model.ReportCheck{Name: model.ReportCheckBullets}
+	if len(resume.Experience) == 0 {
+		check.Status, check.Message = model.ReportStatusSkipped, "No experience to check"
+		return check
+	}
+
+	total := 0
+	for i, e := range resume.Experience {
+		bullets := descriptionBullets(e.Description)
+		if len(bullets) == 0 {
+			check.Suggestions = append(check.Suggestions, fmt.Sprintf("Describe your work as %s with 3-5 bullets of what you achieved", entryName(e)))
+		}
+		for _, b := range bullets {
+			total++
+			if !quantityPattern.MatchString(b) {
+				report.UnquantifiedBullets = append(report.UnquantifiedBullets, model.ResumeBullet{
+					Path: fmt.Sprintf("experience[%d]", i), Position: entryName(e), Text: b,
+				})
+			}
+		}
+	}
+	if total == 0 {
+		check.Message = "No position has a description"
+		return finishCheck(check)
+	}
+
+	n := len(report.UnquantifiedBullets)
+	check.Score = percent(total-n, total)
+	check.Message = fmt.Sprintf("%d of %d bullets have a figure in them", total-n, total)
+	if n > 0 {
+		check.Suggestions = append(check.Suggestions,
+			fmt.Sprintf("Add figures to %d bullets: how many users, how much faster, what it saved (see unquantified_bullets)", n))
+	}
+	return finishCheck(check)
+}
+
+// checkDates looks for positions without dates an ATS can read and for
+// breaks between positions.
+func checkDates(resume *model.ParsedResume, report *model.ResumeReport, now time.Time) model.ReportCheck {
+	check := model.ReportCheck{Name: model.ReportCheckDates}
+	if len(resume.Experience) == 0 {
+		check.Status, check.Message = model.ReportStatusSkipped, "No experience to check"
+		return check
+	}
+
+	type span struct {
+		from, to time.Time
+		name     string
+	}
+	var spans []span
+	undated := 0
+	for _, e := range resume.Experience {
+		period := utils.ParseDateRange(e.Years)
+		if e.Period != nil && e.Period.Start != nil {
+			period = *e.Period
+		}
+		from, to, ok := period.Span(now)
+		if !ok {
+			undated++
+			check.Suggestions = append(check.Suggestions, fmt.Sprintf("Give %s start and end dates as month and year, e.g. \"Mar 2021 - Present\"", entryName(e)))
+			continue
+		}
+		spans = append(spans, span{from, to, entryName(e)})
+	}
+
+	// Walk the positions in start order; a break is measured from the
+	// latest end so far, so overlapping positions leave none
+	sort.Slice(spans, func(i, j int) bool { return spans[i].from.Before(spans[j].from) })
+	for i := 1; i < len(spans); i++ {
+		last, next := spans[0], spans[i]
+		for _, sp := range spans[1:i] {
+			if sp.to.After(last.to) {
+				last = sp
+			}
+		}
+		if months := model.MonthsBetween(last.to, next.from); months >= minGapMonths {
+			report.Gaps = append(report.Gaps, model.EmploymentGap{After: last.name, Before: next.name, From: last.to, To: next.from, Months: months})
+			check.Suggestions = append(check.Suggestions, fmt.Sprintf("Account for the %d months between %s and %s (study, freelance work, a career break)",
+				months, last.name, next.name))
+		}
+	}
+
+	check.Score = max(0, 100-25*len(report.Gaps)-15*undated)
+	check.Message = "Every position is dated, with no breaks of 6 months or more"
+	if len(report.Gaps) > 0 || undated > 0 {
+		check.Message = fmt.Sprintf("Breaks of 6 months or more: %d; positions without readable dates: %d", len(report.Gaps), undated)
+	}
+	return finishCheck(check)
+}
+
+// checkKeywords compares the resume with the skills the target jobs are
+// tagged with, counting mentions of each skill and its aliases.
+func checkKeywords(subject *reportSubject, jobs []model.Job, report *model.ResumeReport) model.ReportCheck {
+	check := model.ReportCheck{Name: model.ReportCheckKeywords}
+	wanted := map[string]int{}
+	var order []string
+	for _, job := range jobs {
+		for _, tag := range splitSkills(job.Tags) {
+			if wanted[tag] == 0 {
+				order = append(order, tag)
+			}
+			wanted[tag]++
+		}
+	}
+	if len(wanted) == 0 {
+		check.Status, check.Message = model.ReportStatusSkipped, "No target jobs with tags: apply to jobs or pass job_id to compare keywords"
+		return check
+	}
+
+	text := strings.ToLower(subject.text)
+	words := max(report.WordCount, 1)
+	covered, total := 0, 0
+	var missing, stuffed []string
+	for _, keyword := range order {
+		match := model.KeywordMatch{Keyword: keyword, Jobs: wanted[keyword], Mentions: countMentions(text, skillTaxonomy.Names(keyword))}
+		match.Density = math.Round(float64(match.Mentions)/float64(words)*10000) / 100
+		report.Keywords = append(report.Keywords, match)
+
+		total += match.Jobs
+		switch {
+		case match.Mentions == 0:
+			missing = append(missing, keyword)
+		case match.Density > keywordStuffing && match.Mentions >= minStuffedMentions:
+			stuffed = append(stuffed, keyword)
+			fallthrough
+		default:
+			covered += match.Jobs
+		}
+	}
+	// Skills most jobs ask for first
+	sort.SliceStable(report.Keywords, func(i, j int) bool { return report.Keywords[i].Jobs > report.Keywords[j].Jobs })
+	sort.SliceStable(missing, func(i, j int) bool { return wanted[missing[i]] > wanted[missing[j]] })
+
+	check.Score = percent(covered, total)
+	check.Message = fmt.Sprintf("Mentions %d of the %d skills your %d target jobs ask for", len(order)-len(missing), len(order), len(jobs))
+	if len(missing) > 0 {
+		check.Suggestions = append(check.Suggestions, fmt.Sprintf("If you have them, mention %s in your skills and in the bullets where you used them",
+			strings.Join(missing[:min(len(missing), 8)], ", ")))
+	}
+	if len(stuffed) > 0 {
+		check.Score = max(0, check.Score-10)
+		check.Suggestions = append(check.Suggestions, fmt.Sprintf("%s come up so often it reads as keyword stuffing; keep them where you actually used them",
+			strings.Join(stuffed, ", ")))
+	}
+	return finishCheck(check)
+}
+
+// countMentions counts where any of names occurs in text as a whole word,
+// counting text that several names match ("node.js" and "node") once. All
+// are lowercase.
+func countMentions(text string, names []string) int {
+	names = append([]string(nil), names...)
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	taken := make([]bool, len(text))
+	n := 0
+	for _, name := range names {
+		for from := 0; name != ""; {
+			i := strings.Index(text[from:], name)
+			if i < 0 {
+				break
+			}
+			start, end := from+i, from+i+len(name)
+			from = end
+			if taken[start] || taken[end-1] || splitsWord(text, start, name) || splitsWord(text, end, name) {
+				continue
+			}
+			for j := start; j < end; j++ {
+				taken[j] = true
+			}
+			n++
+		}
+	}
+	return n
+}
+
+// splitsWord reports whether a match of name ending or starting at offset i
+// of text runs into a word: "go" in "google", but not "c++" in "c++,".
+func splitsWord(text string, i int, name string) bool {
+	before, _ := utf8.DecodeLastRuneInString(text[:i])
+	after, _ := utf8.DecodeRuneInString(text[i:])
+	first, _ := utf8.DecodeRuneInString(name)
+	last, _ := utf8.DecodeLastRuneInString(name)
+	return isWordRune(before) && isWordRune(after) && (isWordRune(first) || isWordRune(last))
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// checkLength checks the resume is neither thin nor padded.
+func checkLength(report *model.ResumeReport) model.ReportCheck {
+	check := model.ReportCheck{Name: model.ReportCheckLength, Score: 100}
+	words := report.WordCount
+	switch {
+	case words < minResumeWords:
+		check.Score = percent(words, minResumeWords)
+		check.Suggestions = append(check.Suggestions, fmt.Sprintf("At %d words the resume is thin: describe what you built and achieved in each position", words))
+	case words > maxResumeWords:
+		check.Score = max(0, 100-percent(words-maxResumeWords, maxResumeWords))
+		check.Suggestions = append(check.Suggestions, fmt.Sprintf("At %d words the resume is long: cut older positions down to a line and keep bullets to what you achieved", words))
+	}
+	if report.PageCount > maxResumePages {
+		check.Score = min(check.Score, 50)
+		check.Suggestions = append(check.Suggestions, fmt.Sprintf("Fit the resume on %d pages; it has %d", maxResumePages, report.PageCount))
+	}
+	check.Message = fmt.Sprintf("%d words", words)
+	if report.PageCount > 0 {
+		check.Message += fmt.Sprintf(" on %d pages", report.PageCount)
+	}
+	return finishCheck(check)
+}
+
+// finishCheck sets the status of a check from its score.
+func finishCheck(check model.ReportCheck) model.ReportCheck {
+	switch {
+	case check.Score >= 80:
+		check.Status = model.ReportStatusPass
+	case check.Score >= 50:
+		check.Status = model.ReportStatusWarn
+	default:
+		check.Status = model.ReportStatusFail
+	}
+	return check
+}
+
+// scoreReport weighs the checks that ran into the overall score and lists
+// their suggestions, the lowest scoring check first.
+func scoreReport(report *model.ResumeReport) {
+	checks := make([]model.ReportCheck, 0, len(report.Checks))
+	total, weights := 0, 0
+	for _, check := range report.Checks {
+		if check.Status == model.ReportStatusSkipped {
+			continue
+		}
+		total += check.Score * reportWeights[check.Name]
+		weights += reportWeights[check.Name]
+		checks = append(checks, check)
+	}
+	if weights > 0 {
+		report.Score = int(math.Round(float64(total) / float64(weights)))
+	}
+
+	sort.SliceStable(checks, func(i, j int) bool { return checks[i].Score < checks[j].Score })
+	for _, check := range checks {
+		report.Suggestions = append(report.Suggestions, check.Suggestions...)
+	}
+}
+
+func percent(n, of int) int {
+	if of <= 0 {
+		return 100
+	}
+	return int(math.Round(float64(n) * 100 / float64(of)))
+}
+
+// entryName names a position for suggestions: "Backend Engineer at Acme".
+func entryName(e model.ParsedExperience) string {
+	switch {
+	case e.Title != "" && e.Company != "":
+		return e.Title + " at " + e.Company
+	case e.Title != "" || e.Company != "":
+		return e.Title + e.Company
+	}
+	return "an untitled position"
+}
+
+// rewriteBullets asks Gemini for stronger wordings of unquantified bullets.
+// It may suggest where a figure belongs but must not make one up.
+func rewriteBullets(bullets []model.ResumeBullet) ([]model.BulletRewrite, error) {
+	if len(bullets) == 0 {
+		return nil, nil
+	}
+	bullets = bullets[:min(len(bullets), maxBulletRewrites)]
+
+	var list strings.Builder
+	for i, b := range bullets {
+		fmt.Fprintf(&list, "%d. (%s) %s\n", i+1, utils.SanitizeText(b.Position), utils.SanitizeText(b.Text))
+	}
+	raw, err := utils.CallGeminiText(rewritePrompt(list.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	var reply struct {
+		Rewrites []string `json:"rewrites"`
+	}
+	if err := json.Unmarshal([]byte(utils.ExtractJSONObject(raw)), &reply); err != nil {
+		return nil, fmt.Errorf("invalid reply: %v", err)
+	}
+	var rewrites []model.BulletRewrite
+	for i, text := range reply.Rewrites {
+		if i >= len(bullets) {
+			break
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			rewrites = append(rewrites, model.BulletRewrite{Path: bullets[i].Path, Original: bullets[i].Text, Rewrite: text})
+		}
+	}
+	return rewrites, nil
+}
+
+func rewritePrompt(bullets string) string {
+	return fmt.Sprintf(`You are a resume editor. Rewrite each resume bullet below so it starts with a strong action verb and states the result of the work. Do not invent facts or figures: where a number would make the result concrete, write a placeholder in square brackets, such as [X%%] or [N users], for the candidate to fill in. Keep each rewrite to one sentence in the language of the original.
+
+Return only a JSON object with no Markdown fence or commentary: {"rewrites": ["...", "..."]}, one rewrite per bullet, in the same order.
+
+Bullets (the position is in parentheses):
+%s`, bullets)
+}
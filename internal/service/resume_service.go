@@ -0,0 +1,209 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/utils"
+	"gorm.io/gorm"
+)
+
+var ErrResumeNotFound = errors.New("resume not found")
+
+// ResumeService keeps users' original resume documents as numbered versions,
+// with the files in a FileStore and the metadata in the database.
+type ResumeService struct {
+	DB    *gorm.DB
+	Files FileStore
+}
+
+// Upload parses the file at path and stores it as the user's next resume
+// version; the first version becomes the primary one. Uploading the same
+// document again returns the existing version with created set to false.
+// The caller still owns (and removes) the file at path.
+func (s *ResumeService) Upload(userID uuid.UUID, path, fileName string, opts ParseOptions) (resume *model.Resume, created bool, err error) {
+	if err := s.DB.Select("id").First(&model.User{}, "id = ?", userID).Error; err != nil {
+		return nil, false, err
+	}
+
+	sum, size, err := hashFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var existing model.Resume
+	err = s.DB.Where("user_id = ? AND sha256 = ?", userID, sum).Order("version desc").First(&existing).Error
+	if err == nil {
+		return &existing, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+
+	result, err := ParseResume(path, opts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	resume = &model.Resume{
+		ID:       uuid.New(),
+		UserID:   userID,
+		FileName: fileName,
+		Format:   result.Format,
+		SHA256:   sum,
+		Size:     size,
+		Parsed:   result,
+	}
+	resume.StorageKey = "resumes/" + userID.String() + "/" + resume.ID.String()
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	_, err = s.Files.Put(resume.StorageKey, f)
+	f.Close()
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		var last struct {
+			Version   int
+			Primaries int64
+		}
+		if err := tx.Model(&model.Resume{}).Where("user_id = ?", userID).
+			Select("COALESCE(MAX(version), 0) AS version, COUNT(CASE WHEN is_primary THEN 1 END) AS primaries").
+			Scan(&last).Error; err != nil {
+			return err
+		}
+		resume.Version = last.Version + 1
+		resume.IsPrimary = last.Primaries == 0
+		return tx.Create(resume).Error
+	})
+	if err != nil {
+		s.Files.Delete(resume.StorageKey)
+		return nil, false, err
+	}
+	return resume, true, nil
+}
+
+func hashFile(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// List returns the user's resume versions, newest first.
+func (s *ResumeService) List(userID uuid.UUID) ([]model.Resume, error) {
+	var resumes []model.Resume
+	err := s.DB.Where("user_id = ?", userID).Order("version desc").Find(&resumes).Error
+	return resumes, err
+}
+
+func (s *ResumeService) Get(userID, resumeID uuid.UUID) (*model.Resume, error) {
+	return findResume(s.DB, userID, resumeID)
+}
+
+func findResume(db *gorm.DB, userID, resumeID uuid.UUID) (*model.Resume, error) {
+	var resume model.Resume
+	err := db.Where("id = ? AND user_id = ?", resumeID, userID).First(&resume).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrResumeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &resume, nil
+}
+
+// SetPrimary makes one version the user's primary resume.
+func (s *ResumeService) SetPrimary(userID, resumeID uuid.UUID) (*model.Resume, error) {
+	var resume *model.Resume
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if resume, err = findResume(tx, userID, resumeID); err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Resume{}).Where("user_id = ? AND id <> ?", userID, resumeID).
+			Update("is_primary", false).Error; err != nil {
+			return err
+		}
+		resume.IsPrimary = true
+		return tx.Model(resume).Update("is_primary", true).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resume, nil
+}
+
+// CanDownload reports whether viewer may download the resume: its owner
+// can, and so can the recruiter of any job it was submitted to.
+func (s *ResumeService) CanDownload(viewerID uuid.UUID, resume *model.Resume) (bool, error) {
+	if viewerID == resume.UserID {
+		return true, nil
+	}
+
+	var count int64
+	err := s.DB.Model(&model.JobApplication{}).
+		Joins("JOIN jobs ON jobs.id = job_applications.job_id").
+		Where("job_applications.resume_id = ? AND jobs.recruiter_id = ?", resume.ID, viewerID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Open returns the stored document of a resume version.
+func (s *ResumeService) Open(resume *model.Resume) (io.ReadCloser, error) {
+	return s.Files.Open(resume.StorageKey)
+}
+
+// Primary returns the user's primary resume version, or the newest one if
+// none is primary.
+func (s *ResumeService) Primary(userID uuid.UUID) (*model.Resume, error) {
+	var resume model.Resume
+	err := s.DB.Where("user_id = ?", userID).Order("is_primary desc, version desc").First(&resume).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrResumeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &resume, nil
+}
+
+// Document extracts the text of a stored resume version again.
+func (s *ResumeService) Document(resume *model.Resume) (*utils.Document, error) {
+	rc, err := s.Open(resume)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	tmp, err := os.CreateTemp("", "resume-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = io.Copy(tmp, rc)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return utils.ExtractDocument(tmp.Name())
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// defaultExchangeRates seeds the exchange_rates table on first start.
+// Rates are maintained locally through POST /exchange-rates afterwards.
+var defaultExchangeRates = []model.ExchangeRate{
+	{Currency: "USD", USDPerUnit: 1},
+	{Currency: "EUR", USDPerUnit: 1.08},
+	{Currency: "GBP", USDPerUnit: 1.27},
+	{Currency: "CHF", USDPerUnit: 1.12},
+	{Currency: "CAD", USDPerUnit: 0.73},
+	{Currency: "AUD", USDPerUnit: 0.66},
+	{Currency: "SGD", USDPerUnit: 0.74},
+	{Currency: "AED", USDPerUnit: 0.2723},
+	{Currency: "INR", USDPerUnit: 0.012},
+	{Currency: "JPY", USDPerUnit: 0.0067},
+	{Currency: "BRL", USDPerUnit: 0.18},
+	{Currency: "USDC", USDPerUnit: 1, IsCrypto: true},
+	{Currency: "USDT", USDPerUnit: 1, IsCrypto: true},
+	{Currency: "DAI", USDPerUnit: 1, IsCrypto: true},
+	{Currency: "ETH", USDPerUnit: 2500, IsCrypto: true},
+	{Currency: "BTC", USDPerUnit: 60000, IsCrypto: true},
+	{Currency: "SOL", USDPerUnit: 150, IsCrypto: true},
+	{Currency: "POL", USDPerUnit: 0.5, IsCrypto: true},
+}
+
+type ExchangeRateService struct {
+	DB *gorm.DB
+}
+
+// SeedDefaults inserts the built-in rates that are not in the table yet.
+func (s *ExchangeRateService) SeedDefaults() error {
+	now := time.Now()
+	rates := make([]model.ExchangeRate, len(defaultExchangeRates))
+	for i, r := range defaultExchangeRates {
+		r.UpdatedAt = now
+		rates[i] = r
+	}
+	return s.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&rates).Error
+}
+
+func (s *ExchangeRateService) List() ([]model.ExchangeRate, error) {
+	var rates []model.ExchangeRate
+	err := s.DB.Order("currency").Find(&rates).Error
+	return rates, err
+}
+
+// Upsert creates or updates the rate for a currency.
+func (s *ExchangeRateService) Upsert(rate *model.ExchangeRate) error {
+	rate.Currency = strings.ToUpper(strings.TrimSpace(rate.Currency))
+	if rate.Currency == "" || rate.USDPerUnit <= 0 {
+		return fmt.Errorf("currency and a positive usd_per_unit are required")
+	}
+	rate.UpdatedAt = time.Now()
+	return s.DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(rate).Error
+}
+
+// Rates returns all rates keyed by currency.
+func (s *ExchangeRateService) Rates() (map[string]model.ExchangeRate, error) {
+	return loadExchangeRates(s.DB)
+}
+
+func loadExchangeRates(db *gorm.DB) (map[string]model.ExchangeRate, error) {
+	var rates []model.ExchangeRate
+	if err := db.Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	byCurrency := make(map[string]model.ExchangeRate, len(rates))
+	for _, r := range rates {
+		byCurrency[r.Currency] = r
+	}
+	return byCurrency, nil
+}
+
+// NormalizeSalary converts a job's salary range to an annual amount in the
+// target currency. It returns false when the job has no salary or a rate is missing.
+func NormalizeSalary(job model.Job, target string, rates map[string]model.ExchangeRate) (*model.NormalizedSalary, bool) {
+	if job.SalaryMin == 0 && job.SalaryMax == 0 {
+		return nil, false
+	}
+
+	from, ok := rates[salaryCurrency(job)]
+	if !ok {
+		return nil, false
+	}
+	to, ok := rates[target]
+	if !ok {
+		return nil, false
+	}
+	perYear, ok := model.PayPeriodsPerYear[salaryPeriod(job)]
+	if !ok {
+		return nil, false
+	}
+
+	convert := func(amount float64) float64 {
+		return math.Round(amount * perYear * from.USDPerUnit / to.USDPerUnit)
+	}
+
+	lo, hi := job.SalaryMin, job.SalaryMax
+	if hi == 0 {
+		hi = lo
+	}
+	if lo == 0 {
+		lo = hi
+	}
+	return &model.NormalizedSalary{
+		Currency: target,
+		Period:   model.PayPeriodYear,
+		Min:      convert(lo),
+		Max:      convert(hi),
+	}, true
+}
+
+// ValidateSalary fills in the default currency and period and checks them
+// against the known rates.
+func ValidateSalary(job *model.Job, rates map[string]model.ExchangeRate) error {
+	job.SalaryCurrency = salaryCurrency(*job)
+	job.SalaryPeriod = salaryPeriod(*job)
+
+	rate, ok := rates[job.SalaryCurrency]
+	if !ok {
+		return fmt.Errorf("Unsupported salary currency: %s", job.SalaryCurrency)
+	}
+	if _, ok := model.PayPeriodsPerYear[job.SalaryPeriod]; !ok {
+		return fmt.Errorf("Invalid salary_period. Must be hour, day, week, month or year")
+	}
+	if job.SalaryMin < 0 || job.SalaryMax < 0 || (job.SalaryMax > 0 && job.SalaryMin > job.SalaryMax) {
+		return fmt.Errorf("Invalid salary range")
+	}
+	if !rate.IsCrypto && (job.TokenChain != "" || job.TokenContract != "") {
+		return fmt.Errorf("token_chain and token_contract are only valid for token-denominated pay")
+	}
+	return nil
+}
+
+func salaryCurrency(job model.Job) string {
+	if c := strings.ToUpper(strings.TrimSpace(job.SalaryCurrency)); c != "" {
+		return c
+	}
+	return "USD"
+}
+
+func salaryPeriod(job model.Job) string {
+	if p := strings.ToLower(strings.TrimSpace(job.SalaryPeriod)); p != "" {
+		return p
+	}
+	return model.PayPeriodYear
+}
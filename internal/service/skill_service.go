@@ -0,0 +1,390 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"github.com/satyam-svg/resume-parser/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrSkillNotFound = errors.New("skill not found")
+	ErrInvalidSkill  = errors.New("invalid skill")
+	ErrSkillConflict = errors.New("name or alias already belongs to another skill")
+)
+
+// maxSkillLength keeps free text that is clearly not a skill out of the
+// unknown skills report
+const maxSkillLength = 60
+
+// skillTaxonomy, once set by UseSkillTaxonomy, normalizes profile skills and
+// job tags
+var skillTaxonomy *SkillService
+
+// UseSkillTaxonomy makes NormalizeSkills map skills onto the taxonomy in s.
+func UseSkillTaxonomy(s *SkillService) {
+	skillTaxonomy = s
+}
+
+// NormalizeSkills rewrites a comma-separated skill list ("golang, JS, go")
+// with the taxonomy's canonical names ("Go, JavaScript"), dropping
+// duplicates. Skills the taxonomy does not know are kept as written and
+// reported for curation.
+func NormalizeSkills(list string) string {
+	return strings.Join(skillTaxonomy.Normalize(splitSkills(list)), ", ")
+}
+
+// SkillService keeps the skill taxonomy: canonical skill names with their
+// aliases, category and parent skill. Lookups use an in-memory index of the
+// table, rebuilt after every change.
+type SkillService struct {
+	DB *gorm.DB
+
+	mu    sync.Mutex
+	index map[string]string // folded name or alias -> canonical name, nil until loaded
+}
+
+// SeedDefaults inserts the built-in skills that are not in the table yet.
+func (s *SkillService) SeedDefaults() error {
+	now := time.Now()
+	skills := utils.DefaultSkills()
+	for i := range skills {
+		skills[i].UpdatedAt = now
+	}
+	err := s.DB.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&skills, 100).Error
+	s.invalidate()
+	return err
+}
+
+// lookupIndex returns the alias index, loading it if needed, or nil without
+// a taxonomy.
+func (s *SkillService) lookupIndex() map[string]string {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.index != nil {
+		return s.index
+	}
+
+	var skills []model.Skill
+	if err := s.DB.Find(&skills).Error; err != nil {
+		log.Printf("⚠️ Failed to load skill taxonomy: %v", err)
+		return nil
+	}
+	index := make(map[string]string, 4*len(skills))
+	for _, skill := range skills {
+		for _, alias := range skill.Aliases {
+			index[utils.SkillKey(alias)] = skill.Name
+		}
+	}
+	// Canonical names win over another skill's alias
+	for _, skill := range skills {
+		index[utils.SkillKey(skill.Name)] = skill.Name
+	}
+	s.index = index
+	return index
+}
+
+func (s *SkillService) invalidate() {
+	s.mu.Lock()
+	s.index = nil
+	s.mu.Unlock()
+}
+
+// Resolve returns the canonical name of a skill or alias.
+func (s *SkillService) Resolve(name string) (string, bool) {
+	canonical, ok := s.lookupIndex()[utils.SkillKey(name)]
+	return canonical, ok
+}
+
+// Names returns the folded names a skill goes by: its own and, when the
+// taxonomy knows it, its aliases.
+func (s *SkillService) Names(name string) []string {
+	key := utils.SkillKey(name)
+	names := []string{key}
+	index := s.lookupIndex()
+	canonical, ok := index[key]
+	if !ok {
+		return names
+	}
+	for alias, c := range index {
+		if c == canonical && alias != key {
+			names = append(names, alias)
+		}
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// maxSkillWords is the most words a skill name spans ("Google Cloud Platform")
+const maxSkillWords = 3
+
+// Find returns the skills text mentions, by canonical name in order of first
+// mention. One- and two-letter names only count written with a capital
+// ("Go", "R"), so "go" and "a" in prose are not skills.
+func (s *SkillService) Find(text string) []string {
+	index := s.lookupIndex()
+	var words []string
+	for _, f := range strings.Fields(text) {
+		f = strings.TrimRight(strings.TrimLeft(f, "([{\"'"), ".,;:!?)]}\"'")
+		if f != "" {
+			words = append(words, f)
+		}
+	}
+
+	found := []string{}
+	seen := map[string]bool{}
+	for i := 0; i < len(words); i++ {
+		// Longest match first, so "Google Cloud Platform" is not read as "Google Cloud"
+		for n := min(maxSkillWords, len(words)-i); n > 0; n-- {
+			phrase := strings.Join(words[i:i+n], " ")
+			name, ok := index[utils.SkillKey(phrase)]
+			if !ok || len(phrase) <= 2 && phrase == strings.ToLower(phrase) {
+				continue
+			}
+			if !seen[name] {
+				seen[name] = true
+				found = append(found, name)
+			}
+			i += n - 1
+			break
+		}
+	}
+	return found
+}
+
+// Normalize maps skills onto their canonical names, keeping their order and
+// dropping duplicates. Unknown skills are kept as written and reported.
+func (s *SkillService) Normalize(skills []string) []string {
+	out, unknown := s.canonicalize(skills)
+	s.reportUnknown(unknown)
+	return out
+}
+
+// canonicalize is Normalize without the report; it also returns the skills
+// the taxonomy does not know.
+func (s *SkillService) canonicalize(skills []string) (out, unknown []string) {
+	index := s.lookupIndex()
+	out = []string{}
+	seen := map[string]bool{}
+	for _, skill := range skills {
+		skill = strings.TrimSpace(skill)
+		if skill == "" {
+			continue
+		}
+		name, ok := index[utils.SkillKey(skill)]
+		if !ok {
+			name = skill
+		}
+		if seen[utils.SkillKey(name)] {
+			continue
+		}
+		seen[utils.SkillKey(name)] = true
+		out = append(out, name)
+		if !ok && index != nil && len(skill) <= maxSkillLength {
+			unknown = append(unknown, skill)
+		}
+	}
+	return out, unknown
+}
+
+// reportUnknown counts skills that matched nothing in the taxonomy.
+func (s *SkillService) reportUnknown(names []string) {
+	now := time.Now()
+	for _, name := range names {
+		entry := model.UnknownSkill{Key: utils.SkillKey(name), Name: name, Count: 1, FirstSeen: now, LastSeen: now}
+		err := s.DB.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "skill_key"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"count":     gorm.Expr("unknown_skills.count + 1"),
+				"last_seen": now,
+			}),
+		}).Create(&entry).Error
+		if err != nil {
+			log.Printf("⚠️ Failed to record unknown skill %q: %v", name, err)
+			return
+		}
+	}
+}
+
+// List returns the taxonomy, optionally only one category, with each
+// skill's children filled in.
+func (s *SkillService) List(category string) ([]model.Skill, error) {
+	var all []model.Skill
+	if err := s.DB.Order("name").Find(&all).Error; err != nil {
+		return nil, err
+	}
+	children := childrenOf(all)
+
+	skills := []model.Skill{}
+	for _, skill := range all {
+		if category != "" && !strings.EqualFold(skill.Category, category) {
+			continue
+		}
+		skill.Children = children[skill.Name]
+		skills = append(skills, skill)
+	}
+	return skills, nil
+}
+
+func childrenOf(skills []model.Skill) map[string][]string {
+	children := map[string][]string{}
+	for _, skill := range skills {
+		if skill.Parent != "" {
+			children[skill.Parent] = append(children[skill.Parent], skill.Name)
+		}
+	}
+	return children
+}
+
+// Get returns a skill by its name or one of its aliases.
+func (s *SkillService) Get(name string) (*model.Skill, error) {
+	canonical, ok := s.Resolve(name)
+	if !ok {
+		return nil, ErrSkillNotFound
+	}
+
+	var skill model.Skill
+	err := s.DB.First(&skill, "name = ?", canonical).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSkillNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.DB.Model(&model.Skill{}).Where("parent = ?", skill.Name).Order("name").
+		Pluck("name", &skill.Children).Error; err != nil {
+		return nil, err
+	}
+	return &skill, nil
+}
+
+// Upsert creates or replaces a skill. Its parent must be in the taxonomy
+// already, and neither its name nor its aliases may belong to another skill.
+// Unknown skills it now covers are removed from the report.
+func (s *SkillService) Upsert(skill *model.Skill) error {
+	skill.Name = strings.Join(strings.Fields(skill.Name), " ")
+	skill.Category = strings.ToLower(strings.TrimSpace(skill.Category))
+	if skill.Name == "" || len(skill.Name) > maxSkillLength {
+		return fmt.Errorf("%w: name is required and at most %d characters", ErrInvalidSkill, maxSkillLength)
+	}
+	index := s.lookupIndex()
+	if index == nil {
+		return errors.New("skill taxonomy unavailable")
+	}
+
+	nameKey := utils.SkillKey(skill.Name)
+	if owner, ok := index[nameKey]; ok && owner != skill.Name {
+		return fmt.Errorf("%w: %q is %s", ErrSkillConflict, skill.Name, owner)
+	}
+
+	keys := []string{nameKey}
+	aliases := model.StringList{}
+	for _, alias := range skill.Aliases {
+		key := utils.SkillKey(alias)
+		if key == "" || containsFold(keys, key) {
+			continue
+		}
+		if owner, ok := index[key]; ok && owner != skill.Name {
+			return fmt.Errorf("%w: %q is %s", ErrSkillConflict, alias, owner)
+		}
+		keys = append(keys, key)
+		aliases = append(aliases, strings.TrimSpace(alias))
+	}
+	skill.Aliases = aliases
+
+	if skill.Parent != "" {
+		parent, ok := index[utils.SkillKey(skill.Parent)]
+		if !ok {
+			return fmt.Errorf("%w: parent %q is not in the taxonomy", ErrInvalidSkill, skill.Parent)
+		}
+		if err := s.checkParent(skill.Name, parent); err != nil {
+			return err
+		}
+		skill.Parent = parent
+	}
+	skill.Children = nil
+	skill.UpdatedAt = time.Now()
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(skill).Error; err != nil {
+			return err
+		}
+		return tx.Where("skill_key IN ?", keys).Delete(&model.UnknownSkill{}).Error
+	})
+	s.invalidate()
+	return err
+}
+
+// checkParent rejects a parent that is the skill itself or one of its
+// descendants.
+func (s *SkillService) checkParent(name, parent string) error {
+	var skills []model.Skill
+	if err := s.DB.Select("name", "parent").Find(&skills).Error; err != nil {
+		return err
+	}
+	parents := make(map[string]string, len(skills))
+	for _, skill := range skills {
+		parents[skill.Name] = skill.Parent
+	}
+	for p := parent; p != ""; p = parents[p] {
+		if p == name {
+			return fmt.Errorf("%w: %q would become its own ancestor", ErrInvalidSkill, name)
+		}
+	}
+	return nil
+}
+
+// Delete removes a skill; its children move up to its parent.
+func (s *SkillService) Delete(name string) error {
+	canonical, ok := s.Resolve(name)
+	if !ok {
+		return ErrSkillNotFound
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var skill model.Skill
+		if err := tx.First(&skill, "name = ?", canonical).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSkillNotFound
+			}
+			return err
+		}
+		if err := tx.Model(&model.Skill{}).Where("parent = ?", skill.Name).Update("parent", skill.Parent).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&skill).Error
+	})
+	s.invalidate()
+	return err
+}
+
+// Unknown returns the skills that matched nothing in the taxonomy, the most
+// frequent first.
+func (s *SkillService) Unknown(limit int) ([]model.UnknownSkill, error) {
+	unknown := []model.UnknownSkill{}
+	err := s.DB.Order("count desc").Order("last_seen desc").Limit(limit).Find(&unknown).Error
+	return unknown, err
+}
+
+// DismissUnknown removes a skill from the unknown skills report.
+func (s *SkillService) DismissUnknown(name string) error {
+	res := s.DB.Where("skill_key = ?", utils.SkillKey(name)).Delete(&model.UnknownSkill{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrSkillNotFound
+	}
+	return nil
+}
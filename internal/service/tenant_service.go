@@ -0,0 +1,61 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/satyam-svg/resume-parser/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TenantService holds per-tenant settings; tenants without any use the defaults.
+type TenantService struct {
+	DB               *gorm.DB
+	DefaultRedactPII bool
+}
+
+// Settings returns a tenant's settings, or the defaults when it has none.
+func (s *TenantService) Settings(tenantID string) (*model.TenantSettings, error) {
+	settings := model.TenantSettings{TenantID: tenantID, RedactPII: s.DefaultRedactPII}
+	if tenantID == "" {
+		return &settings, nil
+	}
+	err := s.DB.First(&settings, "tenant_id = ?", tenantID).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (s *TenantService) UpdateSettings(settings *model.TenantSettings) error {
+	return s.DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(settings).Error
+}
+
+// TenantFor returns the tenant whose settings apply to a user's request. The
+// requested tenant is only used when the user owns it (its ID is the user's
+// ID) or is an admin; otherwise it is the user's own. Anonymous requests get
+// the defaults.
+func (s *TenantService) TenantFor(userID uuid.UUID, requested string) string {
+	if userID == uuid.Nil {
+		return ""
+	}
+	if requested == "" || requested == userID.String() {
+		return userID.String()
+	}
+	var user model.User
+	if s.DB.Select("role").First(&user, "id = ?", userID).Error == nil && user.Role == "admin" {
+		return requested
+	}
+	return userID.String()
+}
+
+// ParseOptions returns the resume parsing options for a tenant. Should its
+// settings fail to load, PII is redacted rather than risk sending it.
+func (s *TenantService) ParseOptions(tenantID, mode string) ParseOptions {
+	settings, err := s.Settings(tenantID)
+	if err != nil {
+		return ParseOptions{Mode: mode, RedactPII: true}
+	}
+	return ParseOptions{Mode: mode, RedactPII: settings.RedactPII}
+}
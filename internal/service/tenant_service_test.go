@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTenantForIgnoresTenantsOfOthers(t *testing.T) {
+	db := newTestDB(t)
+	owner := createUser(t, db, "owner@example.com", "recruiter")
+	other := createUser(t, db, "other@example.com", "recruiter")
+	admin := createUser(t, db, "admin@example.com", "admin")
+	s := &TenantService{DB: db}
+
+	tests := []struct {
+		name      string
+		user      uuid.UUID
+		requested string
+		want      string
+	}{
+		{"anonymous", uuid.Nil, owner.ID.String(), ""},
+		{"no header", owner.ID, "", owner.ID.String()},
+		{"own tenant", owner.ID, owner.ID.String(), owner.ID.String()},
+		{"another user's tenant", other.ID, owner.ID.String(), other.ID.String()},
+		{"admin", admin.ID, owner.ID.String(), owner.ID.String()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.TenantFor(tt.user, tt.requested); got != tt.want {
+				t.Errorf("TenantFor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,12 +5,19 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"regexp"
 	"strings"
+	"time"
 )
 
+// GeminiClient is the HTTP client for every Gemini call. Its timeout bounds
+// how long a request can wait on the model.
+var GeminiClient = &http.Client{Timeout: 60 * time.Second}
+
 // MatchResult represents a structured match response
 type MatchResult struct {
 	Matches []struct {
@@ -65,8 +72,7 @@ func CallGemini(prompt string) (*MatchResult, error) {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	res, err := client.Do(req)
+	res, err := GeminiClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("❌ Request failed: %v", err)
 	}
@@ -147,8 +153,7 @@ func CallGeminiForRecommendations(prompt string) (*RecommendationResult, error)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	res, err := client.Do(req)
+	res, err := GeminiClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("❌ Request failed: %v", err)
 	}
@@ -184,3 +189,68 @@ func CallGeminiForRecommendations(prompt string) (*RecommendationResult, error)
 
 	return &parsed, nil
 }
+
+// GeminiModel is the model every Gemini call goes to
+const GeminiModel = "gemini-1.5-flash"
+
+// CallGeminiText sends a prompt to Gemini and returns the text of its reply
+func CallGeminiText(prompt string) (string, error) {
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" {
+		return "", errors.New("❌ GEMINI_API_KEY not set in environment")
+	}
+
+	payload := map[string]interface{}{
+		"contents": []map[string]interface{}{
+			{
+				"role": "user",
+				"parts": []map[string]string{
+					{"text": prompt},
+				},
+			},
+		},
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("❌ Failed to marshal payload: %v", err)
+	}
+
+	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1/models/%s:generateContent?key=%s", GeminiModel, apiKey)
+
+	res, err := GeminiClient.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		// The URL carries the API key; keep it out of the error
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return "", fmt.Errorf("❌ Request failed: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(res.Body)
+		return "", fmt.Errorf("Gemini API error (%d): %s", res.StatusCode, body)
+	}
+
+	var result struct {
+		Candidates []struct {
+			Content struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"content"`
+		} `json:"candidates"`
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("❌ Failed to decode response: %v", err)
+	}
+
+	if len(result.Candidates) == 0 || len(result.Candidates[0].Content.Parts) == 0 {
+		return "", errors.New("❌ Gemini returned no content")
+	}
+
+	return result.Candidates[0].Content.Parts[0].Text, nil
+}
@@ -2,13 +2,24 @@ package utils
 
 import "strings"
 
-func CleanGeminiResponse(text string) string {
+// ExtractJSONObject returns the JSON object in an LLM reply, dropping any
+// Markdown code fence or prose around it. The object itself is left
+// untouched; an invalid one is reported by the decoder, not patched here.
+func ExtractJSONObject(text string) string {
 	text = strings.TrimSpace(text)
-	text = strings.TrimPrefix(text, "```json")
-	text = strings.TrimPrefix(text, "```")
-	text = strings.TrimSuffix(text, "```")
-	text = strings.ReplaceAll(text, "\\\"", "\"")
-	text = strings.ReplaceAll(text, "\"{", "{")
-	text = strings.ReplaceAll(text, "}\"", "}")
-	return strings.TrimSpace(text)
+	if start := strings.Index(text, "```"); start >= 0 {
+		inner := text[start+3:]
+		inner = strings.TrimPrefix(inner, "json")
+		if end := strings.Index(inner, "```"); end >= 0 {
+			inner = inner[:end]
+		}
+		text = strings.TrimSpace(inner)
+	}
+
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return text
+	}
+	return text[start : end+1]
 }
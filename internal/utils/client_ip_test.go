@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"net"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies("10.0.0.0/8, 127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		trusted   bool
+		remote    string
+		forwarded []string
+		want      string
+	}{
+		{"no proxies configured", false, "203.0.113.7:5000", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"request not from a proxy", true, "203.0.113.7:5000", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"behind a proxy", true, "10.1.2.3:443", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"spoofed entries before the real client", true, "10.1.2.3:443", []string{"1.1.1.1, 2.2.2.2, 198.51.100.1"}, "198.51.100.1"},
+		{"chain of proxies", true, "127.0.0.1:443", []string{"198.51.100.1, 10.9.9.9", "10.1.1.1"}, "198.51.100.1"},
+		{"garbage in the header", true, "10.1.2.3:443", []string{"not-an-ip"}, "10.1.2.3"},
+		{"proxy without the header", true, "10.1.2.3:443", nil, "10.1.2.3"},
+	}
+	defer func(saved []*net.IPNet) { TrustedProxies = saved }(TrustedProxies)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			TrustedProxies = nil
+			if tt.trusted {
+				TrustedProxies = proxies
+			}
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remote
+			for _, v := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			if got := ClientIP(r); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxiesRejectsGarbage(t *testing.T) {
+	for _, list := range []string{"10.0.0.0/33", "proxy.local", "10.0.0.1,,nope"} {
+		if _, err := ParseTrustedProxies(list); err == nil {
+			t.Errorf("ParseTrustedProxies(%q) accepted it", list)
+		}
+	}
+	if nets, err := ParseTrustedProxies(""); err != nil || len(nets) != 0 {
+		t.Errorf("an empty list gave %v, %v", nets, err)
+	}
+}
@@ -0,0 +1,246 @@
+package utils
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+)
+
+// minLooseMatch is the fewest letters and digits a value needs to be
+// matched regardless of punctuation
+const minLooseMatch = 5
+
+// Document is a resume's extracted text, with where each page's text starts
+// and, for PDFs, the positioned text the reader found on each page.
+type Document struct {
+	Text   string
+	Format string
+	Pages  []DocumentPage // PDF only
+
+	textGlyphs []int32 // PDF only: the glyph of its page behind each byte of Text, -1 for layout breaks
+
+	once  sync.Once
+	plain foldedText // Text lowercased, whitespace runs collapsed to one space
+	loose foldedText // Text lowercased, letters and digits only
+}
+
+// DocumentPage is one page of a PDF.
+type DocumentPage struct {
+	Start  int     // byte offset of the page's text in Document.Text
+	Glyphs []Glyph // in the order the page draws them
+}
+
+// Glyph is a run of text drawn at one position, in PDF points from the
+// bottom left corner of the page.
+type Glyph struct {
+	S    string
+	X, Y float64
+	W    float64
+	Size float64 // font size
+	Font string
+}
+
+// foldedText is text folded for matching, with the offset in the original
+// text of every folded byte (plus one past the end).
+type foldedText struct {
+	s       string
+	offsets []int
+}
+
+func foldText(text string, loose bool) foldedText {
+	var b strings.Builder
+	offsets := make([]int, 0, len(text)+1)
+	space := true // drop leading whitespace
+	for i, r := range text {
+		switch {
+		case isAlnum(r):
+			space = false
+		case loose:
+			continue
+		case unicode.IsSpace(r):
+			if space {
+				continue
+			}
+			space = true
+			r = ' '
+		default:
+			space = false
+		}
+		n := b.Len()
+		b.WriteRune(unicode.ToLower(r))
+		for ; n < b.Len(); n++ {
+			offsets = append(offsets, i)
+		}
+	}
+	return foldedText{s: b.String(), offsets: append(offsets, len(text))}
+}
+
+// index finds needle in the folded text, at or after original offset from,
+// and returns the original offsets of the match in text. A match must not
+// start or end inside a word of text.
+func (f foldedText) index(text, needle string, from int) (start, end int, ok bool) {
+	lo := 0
+	for lo < len(f.s) && f.offsets[lo] < from {
+		lo++
+	}
+	for {
+		i := strings.Index(f.s[lo:], needle)
+		if i < 0 {
+			return 0, 0, false
+		}
+		i += lo
+		last := f.offsets[i+len(needle)-1]
+		_, size := utf8.DecodeRuneInString(text[last:])
+		start, end = f.offsets[i], last+size
+		if !splitsWord(text, start) && !splitsWord(text, end) {
+			return start, end, true
+		}
+		lo = i + 1
+	}
+}
+
+// splitsWord reports whether offset i of text is between two letters or digits.
+func splitsWord(text string, i int) bool {
+	if i == 0 || i == len(text) {
+		return false
+	}
+	before, _ := utf8.DecodeLastRuneInString(text[:i])
+	after, _ := utf8.DecodeRuneInString(text[i:])
+	return isAlnum(before) && isAlnum(after)
+}
+
+func isAlnum(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Locate finds value in the text, ignoring case and whitespace layout,
+// preferring a match at or after offset from. If the exact characters are
+// not there it retries on letters and digits alone, so "+49 30 1234" still
+// finds "+49 (30) 12-34"; exact reports which match was used. Values too
+// short to be told apart from other text are only matched exactly.
+func (d *Document) Locate(value string, from int) (src *model.FieldSource, exact bool) {
+	d.once.Do(func() {
+		d.plain = foldText(d.Text, false)
+		d.loose = foldText(d.Text, true)
+	})
+
+	for i, try := range []struct {
+		text   foldedText
+		value  string
+		minLen int
+	}{
+		{d.plain, foldText(value, false).s, 2},
+		{d.loose, foldText(value, true).s, minLooseMatch},
+	} {
+		needle := strings.TrimSpace(try.value)
+		if len(needle) < try.minLen {
+			continue
+		}
+		start, end, ok := try.text.index(d.Text, needle, from)
+		if !ok && from > 0 {
+			start, end, ok = try.text.index(d.Text, needle, 0)
+		}
+		if ok {
+			return d.source(start, end), i == 0
+		}
+	}
+	return nil, false
+}
+
+// source describes the text between two offsets, with its page and, when
+// the page's glyphs contain it, its bounding box.
+func (d *Document) source(start, end int) *model.FieldSource {
+	src := &model.FieldSource{Start: start, End: end, Text: d.Text[start:end]}
+	for i, page := range d.Pages {
+		if page.Start > start {
+			break
+		}
+		src.Page = i + 1
+	}
+	if src.Page == 0 {
+		return src
+	}
+	page := d.Pages[src.Page-1]
+	if glyphs := d.glyphsBetween(src.Page, start, end); len(glyphs) > 0 {
+		src.BBox = page.boundingBox(glyphs)
+		return src
+	}
+	// Text read without positions: the n-th occurrence on the page in the
+	// text is taken to be the n-th among the glyphs
+	needle := foldText(src.Text, true).s
+	n := strings.Count(foldText(d.Text[page.Start:start], true).s, needle)
+	src.BBox = page.boundingBox(page.findGlyphs(needle, n))
+	return src
+}
+
+// glyphsBetween returns the glyphs behind the text between two offsets on
+// one page.
+func (d *Document) glyphsBetween(page, start, end int) []int {
+	if len(d.textGlyphs) != len(d.Text) {
+		return nil
+	}
+	if page < len(d.Pages) {
+		end = min(end, d.Pages[page].Start)
+	}
+	var glyphs []int
+	for _, g := range d.textGlyphs[start:end] {
+		if g >= 0 {
+			glyphs = append(glyphs, int(g))
+		}
+	}
+	return glyphs
+}
+
+// findGlyphs returns the glyphs of the n-th occurrence (from 0) of the
+// letters and digits in needle among the page's glyphs.
+func (p DocumentPage) findGlyphs(needle string, n int) []int {
+	if needle == "" || len(p.Glyphs) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	var owner []int // glyph of every folded byte
+	for gi, g := range p.Glyphs {
+		folded := foldText(g.S, true).s
+		b.WriteString(folded)
+		for range len(folded) {
+			owner = append(owner, gi)
+		}
+	}
+	s := b.String()
+
+	at := -1
+	for from := 0; n >= 0; n-- {
+		i := strings.Index(s[from:], needle)
+		if i < 0 {
+			return nil
+		}
+		at = from + i
+		from = at + len(needle)
+	}
+	return owner[at : at+len(needle)]
+}
+
+// boundingBox returns the box around glyphs.
+func (p DocumentPage) boundingBox(glyphs []int) *model.BBox {
+	if len(glyphs) == 0 {
+		return nil
+	}
+	box := model.BBox{X0: math.Inf(1), Y0: math.Inf(1), X1: math.Inf(-1), Y1: math.Inf(-1)}
+	for _, gi := range glyphs {
+		g := p.Glyphs[gi]
+		box.X0 = math.Min(box.X0, g.X)
+		box.Y0 = math.Min(box.Y0, g.Y)
+		box.X1 = math.Max(box.X1, g.X+g.W)
+		box.Y1 = math.Max(box.Y1, g.Y+g.Size)
+	}
+	box.X0, box.Y0, box.X1, box.Y1 = round2(box.X0), round2(box.Y0), round2(box.X1), round2(box.Y1)
+	return &box
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
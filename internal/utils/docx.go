@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ExtractTextFromDOCX reads the text of a Word (OOXML) document: page
+// headers first, then the body. Paragraphs become lines, list items are
+// prefixed with "- " and table cells are joined with " | ".
+func ExtractTextFromDOCX(filePath string) (string, error) {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer zr.Close()
+
+	var body *zip.File
+	var headers []*zip.File
+	for _, f := range zr.File {
+		switch {
+		case f.Name == "word/document.xml":
+			body = f
+		case path.Dir(f.Name) == "word" && strings.HasPrefix(path.Base(f.Name), "header") && strings.HasSuffix(f.Name, ".xml"):
+			headers = append(headers, f)
+		}
+	}
+	if body == nil {
+		return "", errors.New("not a Word document: word/document.xml missing")
+	}
+	sort.Slice(headers, func(i, j int) bool { return headers[i].Name < headers[j].Name })
+
+	budget := newDecompressBudget(filePath)
+	var sb strings.Builder
+	seen := make(map[string]bool)
+	for _, h := range headers {
+		text, err := readDOCXPart(h, budget)
+		if err != nil {
+			return "", err
+		}
+		// Documents often repeat the same header for first/even/odd pages
+		if text = strings.TrimSpace(text); text != "" && !seen[text] {
+			seen[text] = true
+			sb.WriteString(text)
+			sb.WriteString("\n\n")
+		}
+	}
+
+	text, err := readDOCXPart(body, budget)
+	if err != nil {
+		return "", err
+	}
+	sb.WriteString(text)
+
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// readDOCXPart renders one part, counting what it decompresses to against
+// the document's budget.
+func readDOCXPart(f *zip.File, budget *decompressBudget) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	return docxText(budget.reader(rc))
+}
+
+// docxText walks WordprocessingML and renders its text content.
+func docxText(r io.Reader) (string, error) {
+	dec := xml.NewDecoder(r)
+
+	var (
+		out       strings.Builder
+		para      strings.Builder
+		inText    bool
+		isList    bool
+		cells     [][]string         // open tables: cells of the current row, innermost last
+		cellTexts []*strings.Builder // open cells, innermost last; pointers, as a nested cell grows the slice
+	)
+
+	flushPara := func() {
+		line := strings.TrimRight(para.String(), " \t")
+		para.Reset()
+		if isList && strings.TrimSpace(line) != "" {
+			line = "- " + strings.TrimSpace(line)
+		}
+		isList = false
+
+		if len(cellTexts) > 0 {
+			cell := cellTexts[len(cellTexts)-1]
+			if cell.Len() > 0 && line != "" {
+				cell.WriteString(" ")
+			}
+			cell.WriteString(strings.TrimSpace(line))
+			return
+		}
+		out.WriteString(line)
+		out.WriteString("\n")
+	}
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				para.WriteString("\t")
+			case "br", "cr":
+				para.WriteString("\n")
+			case "numPr":
+				isList = true
+			case "tr":
+				cells = append(cells, nil)
+			case "tc":
+				cellTexts = append(cellTexts, &strings.Builder{})
+			}
+
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				flushPara()
+			case "tc":
+				if len(cellTexts) == 0 || len(cells) == 0 {
+					continue
+				}
+				text := cellTexts[len(cellTexts)-1].String()
+				cellTexts = cellTexts[:len(cellTexts)-1]
+				cells[len(cells)-1] = append(cells[len(cells)-1], text)
+			case "tr":
+				if len(cells) == 0 {
+					continue
+				}
+				row := strings.Join(cells[len(cells)-1], " | ")
+				cells = cells[:len(cells)-1]
+				if strings.Trim(row, " |") == "" {
+					continue
+				}
+				// A nested table row belongs to the enclosing cell
+				if len(cellTexts) > 0 {
+					cell := cellTexts[len(cellTexts)-1]
+					if cell.Len() > 0 {
+						cell.WriteString(" ")
+					}
+					cell.WriteString(row)
+					continue
+				}
+				out.WriteString(row)
+				out.WriteString("\n")
+			case "tbl":
+				if len(cellTexts) == 0 {
+					out.WriteString("\n")
+				}
+			}
+
+		case xml.CharData:
+			if inText {
+				para.Write(t)
+			}
+		}
+	}
+
+	return out.String(), nil
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeDOCX stores a Word document whose body is the given WordprocessingML.
+func writeDOCX(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "resume.docx")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("word/document.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>` +
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		body + `</w:body></w:document>`))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func docxPara(text string) string {
+	return `<w:p><w:r><w:t>` + text + `</w:t></w:r></w:p>`
+}
+
+func TestDOCXNestedTable(t *testing.T) {
+	// A two-column layout table whose right cell holds another table, after
+	// the left cell already has text
+	inner := `<w:tbl>` +
+		`<w:tr><w:tc>` + docxPara("Go") + `</w:tc><w:tc>` + docxPara("5 years") + `</w:tc></w:tr>` +
+		`<w:tr><w:tc>` + docxPara("Kubernetes") + `</w:tc><w:tc>` + docxPara("3 years") + `</w:tc></w:tr>` +
+		`</w:tbl>`
+	body := docxPara("Jane Doe") +
+		`<w:tbl><w:tr>` +
+		`<w:tc>` + docxPara("SKILLS") + `</w:tc>` +
+		`<w:tc>` + docxPara("Backend Engineer") + inner + docxPara("Acme Corp") + `</w:tc>` +
+		`</w:tr></w:tbl>` +
+		docxPara("References on request")
+
+	text, err := ExtractTextFromDOCX(writeDOCX(t, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Jane Doe\n" +
+		"SKILLS | Backend Engineer Go | 5 years Kubernetes | 3 years Acme Corp\n\n" +
+		"References on request"
+	if text != want {
+		t.Errorf("got:\n%q\nwant:\n%q", text, want)
+	}
+}
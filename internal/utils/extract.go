@@ -0,0 +1,235 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Document formats recognized by content sniffing
+const (
+	FormatPDF      = "pdf"
+	FormatDOCX     = "docx"
+	FormatRTF      = "rtf"
+	FormatHTML     = "html"
+	FormatMarkdown = "markdown"
+	FormatText     = "text"
+	FormatUnknown  = "unknown"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported document format")
+
+// formatContentTypes are the MIME types documents are served with
+var formatContentTypes = map[string]string{
+	FormatPDF:      "application/pdf",
+	FormatDOCX:     "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	FormatRTF:      "application/rtf",
+	FormatHTML:     "text/html",
+	FormatMarkdown: "text/markdown",
+	FormatText:     "text/plain",
+}
+
+// FormatContentType returns the MIME type of a detected document format
+func FormatContentType(format string) string {
+	if ct, ok := formatContentTypes[format]; ok {
+		return ct
+	}
+	return "application/octet-stream"
+}
+
+var (
+	pdfMagic = []byte("%PDF-")
+	zipMagic = []byte("PK\x03\x04")
+	oleMagic = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1} // legacy .doc
+	rtfMagic = []byte(`{\rtf`)
+
+	// Tags that mark a text document as HTML
+	htmlMarkers = []string{"<!doctype html", "<html", "<head", "<body", "<div", "<p>", "<p ", "<h1", "<ul", "<table", "<span"}
+)
+
+// ExtractorVersion changes whenever extraction produces different text for
+// the same file, so results cached by file are parsed again.
+const ExtractorVersion = "2"
+
+// sniffLen is how much of a document is read to detect its format
+const sniffLen = 8192
+
+// DetectFormat sniffs a document's format from its content, ignoring the
+// file name and any client-supplied content type.
+func DetectFormat(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return FormatUnknown, err
+	}
+	defer f.Close()
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return FormatUnknown, err
+	}
+	head = head[:n]
+
+	switch {
+	// The PDF header may be preceded by a few junk bytes
+	case bytes.Contains(head[:min(len(head), 1024)], pdfMagic):
+		return FormatPDF, nil
+	case bytes.HasPrefix(head, zipMagic):
+		if isDOCX(filePath) {
+			return FormatDOCX, nil
+		}
+	case bytes.HasPrefix(head, oleMagic):
+		return FormatUnknown, fmt.Errorf("%w: legacy .doc files are not supported, save as .docx or PDF", ErrUnsupportedFormat)
+	}
+
+	text := bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\xEF\xBB\xBF")), " \t\r\n")
+	switch {
+	case bytes.HasPrefix(text, rtfMagic):
+		return FormatRTF, nil
+	case !isText(text):
+		return FormatUnknown, nil
+	case bytes.HasPrefix(text, []byte("<")) && hasHTMLMarker(text):
+		return FormatHTML, nil
+	case looksLikeMarkdown(string(text)):
+		return FormatMarkdown, nil
+	}
+	return FormatText, nil
+}
+
+// isText reports whether data reads as text rather than binary: no NUL bytes
+// and few control characters.
+func isText(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+	control := 0
+	for _, b := range data {
+		switch {
+		case b == 0:
+			return false
+		case b < 32 && b != '\n' && b != '\r' && b != '\t' && b != '\f':
+			control++
+		}
+	}
+	return control*100 < len(data)
+}
+
+func hasHTMLMarker(data []byte) bool {
+	lower := bytes.ToLower(data)
+	for _, m := range htmlMarkers {
+		if bytes.Contains(lower, []byte(m)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDOCX(filePath string) bool {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return false
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if f.Name == "word/document.xml" {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractText picks an extractor from the sniffed format and returns the
+// document's text along with the detected format.
+func ExtractText(filePath string) (string, string, error) {
+	doc, err := ExtractDocument(filePath)
+	if err != nil {
+		return "", doc.Format, err
+	}
+	return doc.Text, doc.Format, nil
+}
+
+// ExtractDocument is ExtractText keeping, for PDFs, where the text is on
+// each page. The returned document has its Format set even on error.
+// Extraction runs within Limits: a reader that panics on a malformed file
+// fails with ErrMalformedDocument, and one still busy after the timeout is
+// abandoned with ErrExtractionTimeout. At most Limits.MaxConcurrent readers
+// run at once, abandoned ones included; waiting for one to finish counts
+// towards the timeout, and fails with ErrExtractionBusy.
+func ExtractDocument(filePath string) (*Document, error) {
+	format, err := DetectFormat(filePath)
+	doc := &Document{Format: format}
+	if err != nil {
+		return doc, err
+	}
+
+	var timeout <-chan time.Time
+	if Limits.Timeout > 0 {
+		timer := time.NewTimer(Limits.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	slots := extractionSlots()
+	if slots != nil {
+		select {
+		case slots <- struct{}{}:
+		case <-timeout:
+			return doc, fmt.Errorf("%w: no reader free after %s", ErrExtractionBusy, Limits.Timeout)
+		}
+	}
+
+	type extracted struct {
+		doc *Document
+		err error
+	}
+	done := make(chan extracted, 1) // buffered, so an abandoned extraction can still finish
+	go func() {
+		if slots != nil {
+			defer func() { <-slots }() // only once the reader returns, even if abandoned
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				done <- extracted{doc, fmt.Errorf("%w: %v", ErrMalformedDocument, r)}
+			}
+		}()
+		d, err := extractFormat(filePath, format)
+		done <- extracted{d, err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.doc == nil {
+			res.doc = doc
+		}
+		return res.doc, res.err
+	case <-timeout:
+		return doc, fmt.Errorf("%w: gave up after %s", ErrExtractionTimeout, Limits.Timeout)
+	}
+}
+
+func extractFormat(filePath, format string) (*Document, error) {
+	var err error
+	doc := &Document{Format: format}
+	switch format {
+	case FormatPDF:
+		return ExtractPDF(filePath)
+	case FormatDOCX:
+		doc.Text, err = ExtractTextFromDOCX(filePath)
+	case FormatRTF:
+		doc.Text, err = ExtractTextFromRTF(filePath)
+	case FormatHTML:
+		doc.Text, err = ExtractTextFromHTML(filePath)
+	case FormatMarkdown:
+		doc.Text, err = ExtractTextFromMarkdown(filePath)
+	case FormatText:
+		doc.Text, err = ExtractTextFromPlain(filePath)
+	default:
+		return doc, ErrUnsupportedFormat
+	}
+	return doc, err
+}
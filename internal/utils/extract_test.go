@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExtractDocumentWaitsForAFreeReader(t *testing.T) {
+	timeout := Limits.Timeout
+	Limits.Timeout = 50 * time.Millisecond
+	defer func() { Limits.Timeout = timeout }()
+
+	// Readers stuck on earlier documents hold every slot
+	slots := extractionSlots()
+	if slots == nil {
+		t.Skip("extractions are not limited")
+	}
+	for range cap(slots) {
+		slots <- struct{}{}
+	}
+	defer func() {
+		for len(slots) > 0 {
+			<-slots
+		}
+	}()
+
+	path := filepath.Join("testdata", "bullets.pdf")
+	if _, err := ExtractDocument(path); !errors.Is(err, ErrExtractionBusy) {
+		t.Fatalf("with no reader free, err = %v, want ErrExtractionBusy", err)
+	}
+
+	<-slots // one of them returns
+	if _, err := ExtractDocument(path); err != nil {
+		t.Fatalf("with a reader free, err = %v", err)
+	}
+	if len(slots) != cap(slots)-1 {
+		t.Errorf("%d slots taken after the extraction, want %d", len(slots), cap(slots)-1)
+	}
+}
@@ -0,0 +1,202 @@
+package utils
+
+import (
+	"html"
+	"os"
+	"strings"
+)
+
+// Elements whose content is never rendered
+var htmlSkipElements = map[string]bool{
+	"head": true, "script": true, "style": true, "noscript": true, "template": true, "svg": true,
+}
+
+// Elements that start on a new line
+var htmlBlockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "body": true,
+	"dd": true, "div": true, "dl": true, "dt": true, "fieldset": true, "figure": true,
+	"footer": true, "form": true, "header": true, "hr": true, "main": true, "nav": true,
+	"ol": true, "p": true, "pre": true, "section": true, "table": true, "ul": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// ExtractTextFromHTML strips markup from an HTML resume while keeping its
+// structure: blocks and headings on their own lines, list items prefixed with
+// "- " and table cells joined with " | ".
+func ExtractTextFromHTML(filePath string) (string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	return tidyLines(htmlText(decodeText(data))), nil
+}
+
+func htmlText(src string) string {
+	var out strings.Builder
+	var last byte            // last byte written, to avoid doubled spaces and newlines
+	lower := asciiLower(src) // same byte offsets as src, unlike strings.ToLower
+	pre := 0
+	cell := 0
+
+	write := func(s string) {
+		if s != "" {
+			out.WriteString(s)
+			last = s[len(s)-1]
+		}
+	}
+	newline := func() {
+		if last != 0 && last != '\n' {
+			write("\n")
+		}
+	}
+	text := func(s string) {
+		s = html.UnescapeString(s)
+		if pre > 0 {
+			write(s)
+			return
+		}
+		// Collapse whitespace, keeping a single space at the edges so inline
+		// elements stay separated
+		words := strings.Fields(s)
+		if len(words) == 0 {
+			if s != "" && last != 0 && last != '\n' && last != ' ' {
+				write(" ")
+			}
+			return
+		}
+		if isHTMLSpace(s[0]) && last != 0 && last != '\n' && last != ' ' {
+			write(" ")
+		}
+		write(strings.Join(words, " "))
+		if isHTMLSpace(s[len(s)-1]) {
+			write(" ")
+		}
+	}
+
+	for i := 0; i < len(src); {
+		lt := strings.IndexByte(src[i:], '<')
+		if lt < 0 {
+			text(src[i:])
+			break
+		}
+		text(src[i : i+lt])
+		i += lt
+
+		// Comments, doctype and processing instructions
+		if strings.HasPrefix(src[i:], "<!--") {
+			end := strings.Index(src[i:], "-->")
+			if end < 0 {
+				break
+			}
+			i += end + 3
+			continue
+		}
+		if strings.HasPrefix(src[i:], "<!") || strings.HasPrefix(src[i:], "<?") {
+			end := strings.IndexByte(src[i:], '>')
+			if end < 0 {
+				break
+			}
+			i += end + 1
+			continue
+		}
+
+		name, closing := htmlTagName(lower[i+1:])
+		end := htmlTagEnd(src, i)
+		if name == "" || end < 0 {
+			// A bare "<" in text, as in "<5 years"
+			text("<")
+			i++
+			continue
+		}
+		i = end + 1
+
+		if htmlSkipElements[name] && !closing {
+			if close := strings.Index(lower[i:], "</"+name); close >= 0 {
+				i += close
+			} else {
+				i = len(src)
+			}
+			continue
+		}
+
+		switch {
+		case name == "br":
+			write("\n")
+		case name == "li" && !closing:
+			newline()
+			write("- ")
+		case name == "tr":
+			newline()
+			cell = 0
+		case (name == "td" || name == "th") && !closing:
+			if cell > 0 {
+				write(" | ")
+			}
+			cell++
+		case name == "pre":
+			newline()
+			if !closing {
+				pre++
+			} else if pre > 0 {
+				pre--
+			}
+		case len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6':
+			// Headings get a blank line before them
+			newline()
+			if !closing {
+				write("\n")
+			}
+		case htmlBlockElements[name]:
+			newline()
+		}
+	}
+	return out.String()
+}
+
+func asciiLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\f'
+}
+
+// htmlTagEnd returns the index of the '>' closing the tag opened at i,
+// skipping over quoted attribute values.
+func htmlTagEnd(src string, i int) int {
+	var quote byte
+	for j := i + 1; j < len(src); j++ {
+		c := src[j]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return j
+		}
+	}
+	return -1
+}
+
+// htmlTagName reads the element name at the start of a tag, just after its "<".
+func htmlTagName(tag string) (string, bool) {
+	closing := strings.HasPrefix(tag, "/")
+	tag = strings.TrimPrefix(tag, "/")
+	n := 0
+	for n < len(tag) && (tag[n] >= 'a' && tag[n] <= 'z' || tag[n] >= '0' && tag[n] <= '9') {
+		n++
+	}
+	if n == 0 || tag[0] < 'a' || tag[0] > 'z' {
+		return "", closing
+	}
+	return tag[:n], closing
+}
@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidateJSONSchema checks a decoded JSON document against a JSON Schema and
+// returns one message per violation, or nil if the document is valid.
+// It covers the subset of draft 2020-12 the app's schemas use: type,
+// properties, required, additionalProperties, items, enum, minLength,
+// maxLength, pattern, minItems, maxItems, minimum and maximum.
+func ValidateJSONSchema(schema map[string]interface{}, doc interface{}) []string {
+	var problems []string
+	validateSchemaNode(schema, doc, "$", &problems)
+	return problems
+}
+
+func validateSchemaNode(schema map[string]interface{}, v interface{}, path string, problems *[]string) {
+	fail := func(format string, args ...interface{}) {
+		*problems = append(*problems, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if t, ok := schema["type"]; ok {
+		var allowed []string
+		switch t := t.(type) {
+		case string:
+			allowed = []string{t}
+		case []interface{}:
+			for _, s := range t {
+				if s, ok := s.(string); ok {
+					allowed = append(allowed, s)
+				}
+			}
+		}
+		if !jsonTypeIn(v, allowed) {
+			fail("expected %s, got %s", strings.Join(allowed, " or "), jsonTypeOf(v))
+			return
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if fmt.Sprint(e) == fmt.Sprint(v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must be one of %v", enum)
+		}
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		props, _ := schema["properties"].(map[string]interface{})
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, r := range required {
+				if name, ok := r.(string); ok {
+					if _, present := v[name]; !present {
+						fail("missing required property %q", name)
+					}
+				}
+			}
+		}
+
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if sub, ok := props[k].(map[string]interface{}); ok {
+				validateSchemaNode(sub, v[k], path+"."+k, problems)
+				continue
+			}
+			switch extra := schema["additionalProperties"].(type) {
+			case bool:
+				if !extra {
+					fail("unexpected property %q", k)
+				}
+			case map[string]interface{}:
+				validateSchemaNode(extra, v[k], path+"."+k, problems)
+			}
+		}
+
+	case []interface{}:
+		if n, ok := schemaNumber(schema, "minItems"); ok && float64(len(v)) < n {
+			fail("must have at least %v items", n)
+		}
+		if n, ok := schemaNumber(schema, "maxItems"); ok && float64(len(v)) > n {
+			fail("must have at most %v items", n)
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validateSchemaNode(items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+
+	case string:
+		length := float64(utf8.RuneCountInString(v))
+		if n, ok := schemaNumber(schema, "minLength"); ok && length < n {
+			fail("must be at least %v characters", n)
+		}
+		if n, ok := schemaNumber(schema, "maxLength"); ok && length > n {
+			fail("must be at most %v characters", n)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err == nil && !re.MatchString(v) {
+				fail("does not match pattern %s", pattern)
+			}
+		}
+
+	case float64:
+		if n, ok := schemaNumber(schema, "minimum"); ok && v < n {
+			fail("must be >= %v", n)
+		}
+		if n, ok := schemaNumber(schema, "maximum"); ok && v > n {
+			fail("must be <= %v", n)
+		}
+	}
+}
+
+func schemaNumber(schema map[string]interface{}, key string) (float64, bool) {
+	n, ok := schema[key].(float64)
+	return n, ok
+}
+
+func jsonTypeIn(v interface{}, allowed []string) bool {
+	actual := jsonTypeOf(v)
+	for _, t := range allowed {
+		if t == actual {
+			return true
+		}
+		// Every integer is also a number
+		if t == "integer" && actual == "number" && v.(float64) == math.Trunc(v.(float64)) {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonTypeOf(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
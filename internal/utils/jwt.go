@@ -1,7 +1,10 @@
 package utils
 
 import (
+	"errors"
+	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -23,3 +26,24 @@ func GenerateJWT(userID uuid.UUID) string {
 	}
 	return ss
 }
+
+// ParseJWT validates a token issued by GenerateJWT and returns its user ID
+func ParseJWT(tokenString string) (uuid.UUID, error) {
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return jwtKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return uuid.Parse(claims.Subject)
+}
+
+// BearerUserID returns the user of the request's "Authorization: Bearer" token
+func BearerUserID(r *http.Request) (uuid.UUID, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return uuid.Nil, errors.New("missing bearer token")
+	}
+	return ParseJWT(strings.TrimSpace(token))
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"embed"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Languages DetectLanguage knows, as ISO 639-1 codes
+const (
+	LangEnglish    = "en"
+	LangSpanish    = "es"
+	LangGerman     = "de"
+	LangPortuguese = "pt"
+	LangHindi      = "hi"
+)
+
+// LanguageNames names the languages DetectLanguage knows, in English.
+var LanguageNames = map[string]string{
+	LangEnglish:    "English",
+	LangSpanish:    "Spanish",
+	LangGerman:     "German",
+	LangPortuguese: "Portuguese",
+	LangHindi:      "Hindi",
+}
+
+// Sample text of each language, named by its code; the trigrams of these
+// are the detection model
+//
+//go:embed data/languages/*.txt
+var languageSamples embed.FS
+
+const (
+	minLanguageLetters = 20    // less text than this is not told apart
+	maxLanguageRunes   = 20000 // enough of a long document to decide on
+)
+
+// languageModel is how often each trigram of letters occurs in a language.
+type languageModel struct {
+	code    string
+	logProb map[string]float64
+	unseen  float64 // log probability of a trigram the sample does not have
+}
+
+var languageModels = sync.OnceValue(func() []languageModel {
+	files, _ := languageSamples.ReadDir("data/languages")
+	counts := map[string]map[string]int{}
+	vocabulary := map[string]bool{}
+	for _, f := range files {
+		data, err := languageSamples.ReadFile("data/languages/" + f.Name())
+		if err != nil {
+			continue
+		}
+		code := strings.TrimSuffix(f.Name(), path.Ext(f.Name()))
+		counts[code] = map[string]int{}
+		for _, word := range languageWords(string(data)) {
+			trigrams(word, func(g string) {
+				counts[code][g]++
+				vocabulary[g] = true
+			})
+		}
+	}
+
+	// Add-one smoothing over every trigram any sample has
+	var models []languageModel
+	for code, grams := range counts {
+		total := 0
+		for _, n := range grams {
+			total += n
+		}
+		denom := math.Log(float64(total + len(vocabulary)))
+		m := languageModel{code: code, logProb: make(map[string]float64, len(grams)), unseen: -denom}
+		for g, n := range grams {
+			m.logProb[g] = math.Log(float64(n+1)) - denom
+		}
+		models = append(models, m)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].code < models[j].code })
+	return models
+})
+
+// languageWords returns the lowercased words of letters in text, leaving
+// out anything with digits or symbols (emails, URLs, dates).
+func languageWords(text string) []string {
+	var words []string
+	for _, f := range strings.Fields(text) {
+		f = strings.TrimFunc(f, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsMark(r) })
+		if f == "" || strings.IndexFunc(f, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsMark(r) && r != '-' && r != '\'' }) >= 0 {
+			continue
+		}
+		words = append(words, strings.ToLower(f))
+	}
+	return words
+}
+
+// trigrams calls fn with every three-rune run of a word padded with spaces,
+// so " go" and "go " mark where words start and end.
+func trigrams(word string, fn func(string)) {
+	r := []rune(" " + word + " ")
+	for i := 0; i+3 <= len(r); i++ {
+		fn(string(r[i : i+3]))
+	}
+}
+
+// DetectLanguage returns the ISO 639-1 code of the language text is most
+// likely written in, scored on the letter trigrams of its words, and how
+// sure it is: the share of words that read most like that language on
+// their own (English skill names in a Spanish resume lower it). Text too
+// short to tell returns "".
+func DetectLanguage(text string) (string, float64) {
+	models := languageModels()
+	if len(models) == 0 {
+		return "", 0
+	}
+	if r := []rune(text); len(r) > maxLanguageRunes {
+		text = string(r[:maxLanguageRunes])
+	}
+
+	totals := make([]float64, len(models))
+	var wordBest []int // best model of each word
+	letters := 0
+	word := make([]float64, len(models))
+	for _, w := range languageWords(text) {
+		letters += len([]rune(w))
+		for i := range word {
+			word[i] = 0
+		}
+		trigrams(w, func(g string) {
+			for i, m := range models {
+				p, ok := m.logProb[g]
+				if !ok {
+					p = m.unseen
+				}
+				word[i] += p
+			}
+		})
+		best := 0
+		for i := range models {
+			totals[i] += word[i]
+			if word[i] > word[best] {
+				best = i
+			}
+		}
+		wordBest = append(wordBest, best)
+	}
+	if letters < minLanguageLetters {
+		return "", 0
+	}
+
+	best := 0
+	for i := range totals {
+		if totals[i] > totals[best] {
+			best = i
+		}
+	}
+	agree := 0
+	for _, b := range wordBest {
+		if b == best {
+			agree++
+		}
+	}
+	return models[best].code, math.Round(float64(agree)/float64(len(wordBest))*100) / 100
+}
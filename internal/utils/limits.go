@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Documents extraction refuses. A corrupt or hostile file fails with one of
+// these the same way every time, so it is not worth retrying.
+var (
+	ErrTooManyPages      = errors.New("document has too many pages")
+	ErrDecompressionBomb = errors.New("document decompresses to too much data")
+	ErrMalformedDocument = errors.New("document is corrupt or malformed")
+	ErrExtractionTimeout = errors.New("reading the document took too long")
+)
+
+// ErrExtractionBusy means every extraction slot stayed taken until the
+// timeout. Unlike the errors above it says nothing about the document.
+var ErrExtractionBusy = errors.New("too many documents are being read")
+
+// ExtractionLimits bound the work extracting one document's text may take.
+type ExtractionLimits struct {
+	MaxPages     int           // PDF pages
+	MaxExpansion int64         // decompressed bytes allowed per byte of the file
+	Timeout      time.Duration // 0 for none
+
+	// Extractions running at once, across all documents, 0 for no limit.
+	// Set before the first extraction.
+	MaxConcurrent int
+}
+
+// Limits applies to every extraction.
+var Limits = ExtractionLimits{MaxPages: 50, MaxExpansion: 100, Timeout: 30 * time.Second, MaxConcurrent: 8}
+
+// extractionSlots holds a token per running extraction, nil if they are
+// not limited. A timed-out extraction keeps its slot until its reader
+// returns, so readers stuck on hostile files cannot pile up.
+var extractionSlots = sync.OnceValue(func() chan struct{} {
+	if Limits.MaxConcurrent <= 0 {
+		return nil
+	}
+	return make(chan struct{}, Limits.MaxConcurrent)
+})
+
+// minDecompressBudget is how much any file may decompress to, however small
+const minDecompressBudget = 16 << 20
+
+// IsRejectedDocument reports whether err is extraction refusing the
+// document itself rather than failing to read the file.
+func IsRejectedDocument(err error) bool {
+	return errors.Is(err, ErrUnsupportedFormat) || errors.Is(err, ErrTooManyPages) ||
+		errors.Is(err, ErrDecompressionBomb) || errors.Is(err, ErrMalformedDocument) ||
+		errors.Is(err, ErrExtractionTimeout)
+}
+
+// decompressBudget is how many decompressed bytes may still be read from
+// one document, across all its streams or parts.
+type decompressBudget struct {
+	left, limit int64
+}
+
+// newDecompressBudget allows a file Limits.MaxExpansion times its size.
+func newDecompressBudget(filePath string) *decompressBudget {
+	limit := int64(minDecompressBudget)
+	if info, err := os.Stat(filePath); err == nil {
+		limit = max(info.Size()*Limits.MaxExpansion, limit)
+	}
+	return &decompressBudget{left: limit, limit: limit}
+}
+
+// reader reads r until the budget runs out, then fails with
+// ErrDecompressionBomb.
+func (b *decompressBudget) reader(r io.Reader) io.Reader {
+	return &budgetReader{r: r, budget: b}
+}
+
+type budgetReader struct {
+	r      io.Reader
+	budget *decompressBudget
+}
+
+func (br *budgetReader) Read(p []byte) (int, error) {
+	b := br.budget
+	if b.left <= 0 {
+		return 0, fmt.Errorf("%w: more than %d MB", ErrDecompressionBomb, b.limit>>20)
+	}
+	if int64(len(p)) > b.left {
+		p = p[:b.left]
+	}
+	n, err := br.r.Read(p)
+	b.left -= int64(n)
+	return n, err
+}
@@ -0,0 +1,301 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+)
+
+//go:embed data/gazetteer.csv
+var gazetteerCSV []byte
+
+//go:embed data/countries.csv
+var countriesCSV []byte
+
+type place struct {
+	City      string
+	Region    string
+	Country   string
+	Latitude  float64
+	Longitude float64
+}
+
+type gazetteer struct {
+	cities    map[string][]place // normalized name/alias -> cities
+	regions   map[string][]place // normalized region -> region (no coordinates)
+	countries map[string]place   // normalized name/alias/code -> country centroid
+}
+
+var (
+	gazOnce sync.Once
+	gaz     *gazetteer
+)
+
+var (
+	remoteRe = regexp.MustCompile(`\b(remote|wfh|work from home|anywhere|distributed|worldwide|global)\b`)
+	hybridRe = regexp.MustCompile(`\bhybrid\b`)
+	onsiteRe = regexp.MustCompile(`\b(on-?site|in[- ]office|office[- ]based)\b`)
+
+	locationSplitRe = regexp.MustCompile(`[,/;|()\[\]]| - | – `)
+	nonNameRe       = regexp.MustCompile(`[^\p{L}\p{N}\s.'-]`)
+)
+
+func loadGazetteer() *gazetteer {
+	gazOnce.Do(func() {
+		g := &gazetteer{
+			cities:    make(map[string][]place),
+			regions:   make(map[string][]place),
+			countries: make(map[string]place),
+		}
+
+		for _, rec := range readDataCSV(countriesCSV) {
+			if len(rec) < 4 {
+				continue
+			}
+			p := place{Country: rec[0], Latitude: parseFloat(rec[2]), Longitude: parseFloat(rec[3])}
+			g.countries[normalizePlaceName(rec[0])] = p
+			g.countries[normalizePlaceName(rec[1])] = p
+			for _, alias := range aliasesOf(rec, 4) {
+				g.countries[alias] = p
+			}
+		}
+
+		seenRegion := make(map[string]bool)
+		for _, rec := range readDataCSV(gazetteerCSV) {
+			if len(rec) < 5 {
+				continue
+			}
+			p := place{City: rec[0], Region: rec[1], Country: rec[2], Latitude: parseFloat(rec[3]), Longitude: parseFloat(rec[4])}
+			g.cities[normalizePlaceName(rec[0])] = append(g.cities[normalizePlaceName(rec[0])], p)
+			for _, alias := range aliasesOf(rec, 5) {
+				g.cities[alias] = append(g.cities[alias], p)
+			}
+
+			regionKey := normalizePlaceName(rec[1])
+			if !seenRegion[regionKey+"|"+rec[2]] {
+				seenRegion[regionKey+"|"+rec[2]] = true
+				g.regions[regionKey] = append(g.regions[regionKey], place{Region: rec[1], Country: rec[2]})
+			}
+		}
+
+		gaz = g
+	})
+	return gaz
+}
+
+func readDataCSV(data []byte) [][]string {
+	var records [][]string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		records = append(records, fields)
+	}
+	return records
+}
+
+func aliasesOf(rec []string, idx int) []string {
+	if len(rec) <= idx || rec[idx] == "" {
+		return nil
+	}
+	var out []string
+	for _, a := range strings.Split(rec[idx], "|") {
+		if a = normalizePlaceName(a); a != "" {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func parseFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+func normalizePlaceName(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = nonNameRe.ReplaceAllString(s, " ")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// DetectWorkMode extracts remote/hybrid/onsite hints from a free-text location.
+// Hybrid wins over remote ("Remote / Hybrid - Berlin" is hybrid).
+func DetectWorkMode(raw string) string {
+	s := strings.ToLower(raw)
+	switch {
+	case hybridRe.MatchString(s):
+		return model.WorkModeHybrid
+	case remoteRe.MatchString(s):
+		return model.WorkModeRemote
+	case onsiteRe.MatchString(s):
+		return model.WorkModeOnsite
+	}
+	return ""
+}
+
+// ResolveLocation normalizes a free-text location such as "Bangalore",
+// "Bengaluru, IN" or "Remote (EU)" against the bundled offline gazetteer.
+// Unknown places come back with an empty Precision.
+func ResolveLocation(raw string) model.Location {
+	g := loadGazetteer()
+	loc := model.Location{WorkMode: DetectWorkMode(raw)}
+
+	cleaned := strings.ToLower(raw)
+	for _, re := range []*regexp.Regexp{hybridRe, remoteRe, onsiteRe} {
+		cleaned = re.ReplaceAllString(cleaned, " ")
+	}
+
+	var segments []string
+	for _, seg := range locationSplitRe.Split(cleaned, -1) {
+		if seg = normalizePlaceName(seg); seg != "" {
+			segments = append(segments, seg)
+		}
+	}
+
+	// Country and region hints used to disambiguate cities
+	var country *place
+	var region *place
+	for _, seg := range segments {
+		if c, ok := g.countries[seg]; ok && country == nil {
+			c := c
+			country = &c
+			continue
+		}
+		if rs, ok := g.regions[seg]; ok && region == nil {
+			r := pickPlace(rs, country)
+			region = &r
+		}
+	}
+
+	for _, seg := range segments {
+		candidates := lookupCity(g, seg)
+		if len(candidates) == 0 {
+			continue
+		}
+		if region != nil {
+			candidates = preferRegion(candidates, region.Region)
+		}
+		city := pickPlace(candidates, country)
+		loc.City = city.City
+		loc.Region = city.Region
+		loc.Country = city.Country
+		loc.Latitude = city.Latitude
+		loc.Longitude = city.Longitude
+		loc.Precision = model.PrecisionCity
+		return loc
+	}
+
+	switch {
+	case region != nil:
+		loc.Region = region.Region
+		loc.Country = region.Country
+		loc.Precision = model.PrecisionRegion
+	case country != nil:
+		loc.Country = country.Country
+		loc.Latitude = country.Latitude
+		loc.Longitude = country.Longitude
+		loc.Precision = model.PrecisionCountry
+	default:
+		// Fall back to country names embedded in a longer phrase ("Remote in India")
+		for _, seg := range segments {
+			words := strings.Fields(seg)
+			for n := 3; n >= 1; n-- {
+				for i := 0; i+n <= len(words); i++ {
+					key := strings.Join(words[i:i+n], " ")
+					if c, ok := g.countries[key]; ok && len(key) > 2 {
+						loc.Country = c.Country
+						loc.Latitude = c.Latitude
+						loc.Longitude = c.Longitude
+						loc.Precision = model.PrecisionCountry
+						return loc
+					}
+				}
+			}
+		}
+	}
+
+	return loc
+}
+
+// lookupCity matches a segment, or any run of up to three of its words,
+// against city names and aliases, preferring the longest match.
+func lookupCity(g *gazetteer, seg string) []place {
+	if cs, ok := g.cities[seg]; ok {
+		return cs
+	}
+	words := strings.Fields(seg)
+	for n := min(3, len(words)); n >= 1; n-- {
+		for i := 0; i+n <= len(words); i++ {
+			key := strings.Join(words[i:i+n], " ")
+			if len(key) < 3 {
+				continue
+			}
+			if cs, ok := g.cities[key]; ok {
+				return cs
+			}
+		}
+	}
+	return nil
+}
+
+func preferRegion(candidates []place, region string) []place {
+	for _, c := range candidates {
+		if c.Region == region {
+			return []place{c}
+		}
+	}
+	return candidates
+}
+
+func pickPlace(candidates []place, country *place) place {
+	if country != nil {
+		for _, c := range candidates {
+			if c.Country == country.Country {
+				return c
+			}
+		}
+	}
+	return candidates[0]
+}
+
+// ResolveLocationWithMode resolves raw but keeps an explicitly chosen work mode.
+func ResolveLocationWithMode(raw string, current model.Location) model.Location {
+	loc := ResolveLocation(raw)
+	if current.WorkMode != "" {
+		loc.WorkMode = current.WorkMode
+	}
+	return loc
+}
+
+// FormatLocation prefers the normalized location and falls back to the raw text.
+func FormatLocation(loc model.Location, raw string) string {
+	if s := loc.String(); s != "" {
+		return s
+	}
+	return raw
+}
+
+// HaversineKm returns the great-circle distance between two points in kilometres.
+func HaversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKm = 6371.0
+	toRad := func(d float64) float64 { return d * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return earthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/smtp"
+	"os"
+	"strings"
+)
+
+// ErrMailNotConfigured is returned by SendMail when SMTP_HOST is not set.
+var ErrMailNotConfigured = errors.New("mail is not configured")
+
+// SendMail sends a plain-text email through the SMTP server named by
+// SMTP_HOST and SMTP_PORT (default 587), signing in with SMTP_USERNAME and
+// SMTP_PASSWORD when they are set. MAIL_FROM is the sender.
+func SendMail(to, subject, body string) error {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return ErrMailNotConfigured
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = os.Getenv("SMTP_USERNAME")
+	}
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return fmt.Errorf("invalid mail header")
+	}
+
+	var auth smtp.Auth
+	if user := os.Getenv("SMTP_USERNAME"); user != "" {
+		auth = smtp.PlainAuth("", user, os.Getenv("SMTP_PASSWORD"), host)
+	}
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n", from, to, subject, body)
+	return smtp.SendMail(host+":"+port, auth, from, []string{to}, []byte(msg))
+}
@@ -0,0 +1,520 @@
+package utils
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Layout thresholds, as fractions of the font size unless noted
+const (
+	layoutLineTolerance = 0.4  // baselines this close are one line
+	layoutWordGap       = 0.15 // a gap this wide between glyphs is a space
+	layoutSegmentGap    = 1.5  // a wider gap splits a line into segments (columns, right-aligned dates)
+	layoutParagraphGap  = 1.7  // a wider gap between baselines starts a paragraph
+	layoutHeadingScale  = 1.15 // a line this much larger than the body text is a heading
+	layoutMinGutter     = 10   // points; narrowest gap between columns
+	layoutPageMargin    = 0.08 // share of the page height at the top and bottom where headers and footers sit
+)
+
+var (
+	bulletGlyphs      = "•●▪◦‣■□◆◇►▸○∙·–-*"
+	pageNumberPattern = regexp.MustCompile(`(?i)^[-–\s]*(page\s*)?\d+(\s*(of|/)\s*\d+)?[-–\s]*$`)
+)
+
+// pdfLine is glyphs sharing a baseline, split into segments where they are
+// far apart.
+type pdfLine struct {
+	y, size  float64
+	segments []pdfSegment
+}
+
+type pdfSegment struct {
+	x0, x1 float64
+	glyphs []int // into the page's glyphs, left to right, spaces included
+}
+
+func (l *pdfLine) x0() float64 { return l.segments[0].x0 }
+
+// layoutPage is a page being laid out.
+type layoutPage struct {
+	glyphs      []Glyph
+	bottom, top float64 // of the page's media box
+	lines       []*pdfLine
+}
+
+// positioned reports whether the reader gave the page's glyphs real widths;
+// without them every glyph of a line sits at the same x, and layout is
+// impossible.
+func (p *layoutPage) positioned() bool {
+	var visible, sized int
+	for _, g := range p.glyphs {
+		if strings.TrimSpace(g.S) == "" {
+			continue
+		}
+		visible++
+		if g.W > 0 {
+			sized++
+		}
+	}
+	return visible > 0 && sized*2 >= visible
+}
+
+// groupLines sorts the page's glyphs into lines, top to bottom.
+func (p *layoutPage) groupLines() {
+	order := make([]int, len(p.glyphs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return p.glyphs[order[a]].Y > p.glyphs[order[b]].Y })
+
+	var line []int
+	flush := func() {
+		if l := p.buildLine(line); l != nil {
+			p.lines = append(p.lines, l)
+		}
+		line = nil
+	}
+	for _, gi := range order {
+		if len(line) > 0 {
+			first := p.glyphs[line[0]]
+			if math.Abs(p.glyphs[gi].Y-first.Y) > layoutLineTolerance*math.Max(first.Size, p.glyphs[gi].Size) {
+				flush()
+			}
+		}
+		line = append(line, gi)
+	}
+	flush()
+}
+
+// buildLine splits a line's glyphs into segments, dropping glyphs drawn twice
+// in the same place (a common way to fake bold).
+func (p *layoutPage) buildLine(glyphs []int) *pdfLine {
+	sort.SliceStable(glyphs, func(a, b int) bool { return p.glyphs[glyphs[a]].X < p.glyphs[glyphs[b]].X })
+
+	l := &pdfLine{y: p.glyphs[glyphs[0]].Y}
+	var seg *pdfSegment
+	end := math.Inf(-1) // right edge of the last visible glyph
+	var last Glyph
+	for _, gi := range glyphs {
+		g := p.glyphs[gi]
+		l.size = math.Max(l.size, g.Size)
+		if strings.TrimSpace(g.S) == "" {
+			if seg != nil {
+				seg.glyphs = append(seg.glyphs, gi)
+			}
+			continue
+		}
+		if g.S == last.S && math.Abs(g.X-last.X) < 0.5 && math.Abs(g.Y-last.Y) < 0.5 {
+			continue
+		}
+		if seg == nil || g.X-end > layoutSegmentGap*g.Size {
+			l.segments = append(l.segments, pdfSegment{x0: g.X})
+			seg = &l.segments[len(l.segments)-1]
+		}
+		seg.glyphs = append(seg.glyphs, gi)
+		end = math.Max(end, g.X+g.W)
+		seg.x1 = end
+		last = g
+	}
+	if len(l.segments) == 0 {
+		return nil
+	}
+	return l
+}
+
+// segmentText is the text of a segment with spaces where glyphs are apart.
+func (p *layoutPage) segmentText(seg pdfSegment) string {
+	var t layoutText
+	p.writeSegment(&t, seg)
+	return string(t.b)
+}
+
+func (p *layoutPage) lineText(l *pdfLine) string {
+	parts := make([]string, len(l.segments))
+	for i, seg := range l.segments {
+		parts[i] = p.segmentText(seg)
+	}
+	return strings.Join(parts, "\t")
+}
+
+// writeSegment writes a segment's glyphs, with a space where they are apart.
+func (p *layoutPage) writeSegment(out *layoutText, seg pdfSegment) {
+	end := math.Inf(1)
+	space := false
+	for _, gi := range seg.glyphs {
+		g := p.glyphs[gi]
+		if strings.TrimSpace(g.S) == "" {
+			space = true
+			continue
+		}
+		if !math.IsInf(end, 1) && (space || g.X-end > layoutWordGap*g.Size) {
+			out.sep(" ")
+		}
+		out.add(g.S, gi)
+		end = g.X + g.W
+		space = false
+	}
+}
+
+// findGutter looks for a vertical gap splitting lines into two columns of
+// real text. Lines that cross it (a full-width name or heading) are allowed
+// as long as they are few.
+func (p *layoutPage) findGutter(lines []*pdfLine) (float64, bool) {
+	if len(lines) < 6 {
+		return 0, false
+	}
+	left, right := math.Inf(1), math.Inf(-1)
+	for _, l := range lines {
+		for _, seg := range l.segments {
+			left, right = math.Min(left, seg.x0), math.Max(right, seg.x1)
+		}
+	}
+	width := right - left
+	if width < 4*layoutMinGutter {
+		return 0, false
+	}
+
+	// The longest run of candidate positions crossed by the fewest lines
+	const step = 2.0
+	best, bestRun, bestX := len(lines)+1, 0, 0.0
+	run, runStart := 0, 0.0
+	prevCrossing := -1
+	for x := left + 0.2*width; x <= left+0.8*width; x += step {
+		crossing := 0
+		for _, l := range lines {
+			for _, seg := range l.segments {
+				if seg.x0 < x && x < seg.x1 {
+					crossing++
+					break
+				}
+			}
+		}
+		if crossing != prevCrossing {
+			run, runStart = 0, x
+		}
+		prevCrossing = crossing
+		run++
+		if crossing < best || crossing == best && run > bestRun {
+			best, bestRun, bestX = crossing, run, runStart+float64(run-1)*step/2
+		}
+	}
+	if float64(bestRun)*step < layoutMinGutter || float64(best) > 0.2*float64(len(lines)) {
+		return 0, false
+	}
+
+	// Both sides need enough text to be columns, not a title and its dates
+	var leftLines, rightLines, leftChars, rightChars int
+	for _, l := range lines {
+		hasLeft, hasRight := false, false
+		for _, seg := range l.segments {
+			n := utf8.RuneCountInString(p.segmentText(seg))
+			switch {
+			case seg.x1 <= bestX:
+				hasLeft, leftChars = true, leftChars+n
+			case seg.x0 >= bestX:
+				hasRight, rightChars = true, rightChars+n
+			}
+		}
+		if hasLeft {
+			leftLines++
+		}
+		if hasRight {
+			rightLines++
+		}
+	}
+	total := float64(leftChars + rightChars)
+	if leftLines < 3 || rightLines < 3 || float64(leftChars) < 0.2*total || float64(rightChars) < 0.2*total {
+		return 0, false
+	}
+	return bestX, true
+}
+
+// readingOrder returns the lines as blocks read one after another: the page
+// top to bottom, and within a band of columns the left column first.
+func (p *layoutPage) readingOrder(lines []*pdfLine, depth int) [][]*pdfLine {
+	gutter, ok := 0.0, false
+	if depth < 2 {
+		gutter, ok = p.findGutter(lines)
+	}
+	if !ok {
+		return [][]*pdfLine{lines}
+	}
+
+	var blocks [][]*pdfLine
+	var leftBand, rightBand []*pdfLine
+	crossingBlock := -1 // index of the block of lines crossing the gutter being read
+	flush := func() {
+		for _, band := range [][]*pdfLine{leftBand, rightBand} {
+			if len(band) > 0 {
+				blocks = append(blocks, p.readingOrder(band, depth+1)...)
+				crossingBlock = -1
+			}
+		}
+		leftBand, rightBand = nil, nil
+	}
+	for _, l := range lines {
+		var leftSegs, rightSegs []pdfSegment
+		crossing := false
+		for _, seg := range l.segments {
+			switch {
+			case seg.x1 <= gutter:
+				leftSegs = append(leftSegs, seg)
+			case seg.x0 >= gutter:
+				rightSegs = append(rightSegs, seg)
+			default:
+				crossing = true
+			}
+		}
+		if crossing {
+			flush()
+			if crossingBlock < 0 {
+				blocks = append(blocks, nil)
+				crossingBlock = len(blocks) - 1
+			}
+			blocks[crossingBlock] = append(blocks[crossingBlock], l)
+			continue
+		}
+		if len(leftSegs) > 0 {
+			leftBand = append(leftBand, &pdfLine{y: l.y, size: l.size, segments: leftSegs})
+		}
+		if len(rightSegs) > 0 {
+			rightBand = append(rightBand, &pdfLine{y: l.y, size: l.size, segments: rightSegs})
+		}
+	}
+	flush()
+	return blocks
+}
+
+// layoutText is text being built from glyphs, remembering the glyph behind
+// every byte (-1 for spaces and breaks put in by the layout).
+type layoutText struct {
+	b      []byte
+	glyphs []int32
+}
+
+func (t *layoutText) add(s string, glyph int) {
+	t.b = append(t.b, s...)
+	for range len(s) {
+		t.glyphs = append(t.glyphs, int32(glyph))
+	}
+}
+
+func (t *layoutText) sep(s string) {
+	t.b = append(t.b, s...)
+	for range len(s) {
+		t.glyphs = append(t.glyphs, -1)
+	}
+}
+
+// trim drops trailing bytes in cut.
+func (t *layoutText) trim(cut string) {
+	n := len(t.b)
+	for n > 0 && strings.IndexByte(cut, t.b[n-1]) >= 0 {
+		n--
+	}
+	t.b, t.glyphs = t.b[:n], t.glyphs[:n]
+}
+
+// blankLine ends the text with an empty line, unless it is empty.
+func (t *layoutText) blankLine() {
+	t.trim(" \n")
+	if len(t.b) > 0 {
+		t.sep("\n\n")
+	}
+}
+
+// write lays the page's blocks out as text: a line per line, bullets as
+// "- " with their wrapped lines joined, and a blank line before headings,
+// paragraphs and columns.
+func (p *layoutPage) write(out *layoutText, blocks [][]*pdfLine, bodySize float64) {
+	for _, block := range blocks {
+		out.blankLine()
+		var prev *pdfLine
+		bulletX := math.Inf(1)     // where the open bullet item's bullet is
+		bulletTextX := math.Inf(1) // and where its text starts
+		for _, l := range block {
+			text := p.lineText(l)
+			bullet := isBulletLine(text)
+			heading := !bullet && isHeadingLine(p, l, text, bodySize)
+
+			switch {
+			case prev == nil:
+			case heading || prev.y-l.y > layoutParagraphGap*math.Max(prev.size, l.size):
+				out.blankLine()
+				bulletX, bulletTextX = math.Inf(1), math.Inf(1)
+			case !bullet && l.x0() >= bulletTextX-2 && l.x0() > bulletX+1:
+				// The wrapped line of a bullet item: join it on
+				out.trim(" ")
+				if hyphenated(out.b) && startsLower(text) {
+					out.trim("-")
+				} else {
+					out.sep(" ")
+				}
+				p.writeLine(out, l, false)
+				prev = l
+				continue
+			default:
+				out.sep("\n")
+				bulletX, bulletTextX = math.Inf(1), math.Inf(1)
+			}
+
+			p.writeLine(out, l, bullet)
+			if bullet {
+				bulletX, bulletTextX = l.x0(), bulletTextStart(p, l)
+			}
+			prev = l
+		}
+	}
+}
+
+// writeLine writes a line's segments separated by tabs, with a leading
+// bullet glyph written as "- ".
+func (p *layoutPage) writeLine(out *layoutText, l *pdfLine, bullet bool) {
+	for i, seg := range l.segments {
+		if i > 0 {
+			out.sep("\t")
+		}
+		if bullet && i == 0 {
+			out.sep("- ")
+			seg.glyphs = dropBullet(p, seg.glyphs)
+		}
+		p.writeSegment(out, seg)
+	}
+}
+
+// dropBullet removes the bullet glyph and the spaces after it.
+func dropBullet(p *layoutPage, glyphs []int) []int {
+	for i, gi := range glyphs {
+		if s := strings.TrimSpace(p.glyphs[gi].S); s != "" {
+			glyphs = glyphs[i+1:]
+			break
+		}
+	}
+	for len(glyphs) > 0 && strings.TrimSpace(p.glyphs[glyphs[0]].S) == "" {
+		glyphs = glyphs[1:]
+	}
+	return glyphs
+}
+
+// bulletTextStart is the x where a bullet item's text starts.
+func bulletTextStart(p *layoutPage, l *pdfLine) float64 {
+	rest := dropBullet(p, l.segments[0].glyphs)
+	if len(rest) == 0 {
+		return math.Inf(1)
+	}
+	return p.glyphs[rest[0]].X
+}
+
+func isBulletLine(text string) bool {
+	r, size := utf8.DecodeRuneInString(text)
+	if !strings.ContainsRune(bulletGlyphs, r) {
+		return false
+	}
+	next, _ := utf8.DecodeRuneInString(text[size:])
+	// "-", "*" and "–" only count followed by a space; the others are bullets anyway
+	return strings.ContainsRune("•●▪◦‣■□◆◇►▸○∙·", r) || next == ' '
+}
+
+// isHeadingLine reports whether a line looks like a section heading: short,
+// and in capitals, larger type or bold.
+func isHeadingLine(p *layoutPage, l *pdfLine, text string, bodySize float64) bool {
+	text = strings.TrimSpace(text)
+	words := strings.Fields(text)
+	if len(words) == 0 || len(words) > 5 || utf8.RuneCountInString(text) > 40 || strings.ContainsAny(text[len(text)-1:], ".,;") {
+		return false
+	}
+	if l.size >= layoutHeadingScale*bodySize {
+		return true
+	}
+	letters, upper := 0, 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	if letters >= 3 && upper == letters {
+		return true
+	}
+	return len(words) <= 3 && isBoldFont(p.glyphs[l.segments[0].glyphs[0]].Font)
+}
+
+func isBoldFont(font string) bool {
+	font = strings.ToLower(font)
+	return strings.Contains(font, "bold") || strings.Contains(font, "black") || strings.Contains(font, "heavy")
+}
+
+func hyphenated(b []byte) bool {
+	n := len(b)
+	return n >= 2 && b[n-1] == '-' && unicode.IsLetter(rune(b[n-2]))
+}
+
+func startsLower(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsLower(r)
+}
+
+// bodyTextSize is the font size most text on the pages is set in.
+func bodyTextSize(pages []*layoutPage) float64 {
+	counts := map[float64]int{}
+	for _, p := range pages {
+		for _, g := range p.glyphs {
+			counts[math.Round(g.Size*2)/2]++
+		}
+	}
+	best, size := 0, 0.0
+	for s, n := range counts {
+		if n > best || n == best && s < size {
+			best, size = n, s
+		}
+	}
+	return size
+}
+
+// dropHeadersAndFooters removes lines in the top and bottom margins that
+// repeat on most pages, digits aside ("Jane Doe - Page 2"), and page numbers.
+func dropHeadersAndFooters(pages []*layoutPage) {
+	inMargin := func(p *layoutPage, l *pdfLine) bool {
+		margin := (p.top - p.bottom) * layoutPageMargin
+		return margin > 0 && (l.y > p.top-margin || l.y < p.bottom+margin)
+	}
+	key := func(p *layoutPage, l *pdfLine) string {
+		return strings.Map(func(r rune) rune {
+			if unicode.IsDigit(r) || unicode.IsSpace(r) {
+				return -1
+			}
+			return unicode.ToLower(r)
+		}, p.lineText(l))
+	}
+
+	seen := map[string]int{}
+	for _, p := range pages {
+		onPage := map[string]bool{}
+		for _, l := range p.lines {
+			if inMargin(p, l) {
+				onPage[key(p, l)] = true
+			}
+		}
+		for k := range onPage {
+			seen[k]++
+		}
+	}
+
+	repeated := max(2, (len(pages)+1)/2)
+	for _, p := range pages {
+		kept := p.lines[:0]
+		for _, l := range p.lines {
+			if inMargin(p, l) && (pageNumberPattern.MatchString(p.lineText(l)) || len(pages) > 1 && seen[key(p, l)] >= repeated) {
+				continue
+			}
+			kept = append(kept, l)
+		}
+		p.lines = kept
+	}
+}
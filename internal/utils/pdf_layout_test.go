@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateFixtures = flag.Bool("update", false, "rewrite the PDF fixtures in testdata")
+
+// pdfLayoutFixtures draw the PDFs in testdata. Run the tests with -update
+// after changing one.
+var pdfLayoutFixtures = map[string]func(p *PDFWriter){
+	// A full-width name over a narrow left column and a wide right one,
+	// their lines sharing baselines
+	"two_column.pdf": func(p *PDFWriter) {
+		p.AddPage()
+		p.Text(54, 90, FontHelveticaBold, 20, "Jane Doe")
+		left := []string{"SKILLS", "Go, PostgreSQL", "Kubernetes, Terraform", "gRPC, Kafka", "", "LANGUAGES", "English (native)", "German (B2)"}
+		right := []string{"EXPERIENCE", "Backend Engineer, Acme Corp", "Built the payment API in Go", "Cut latency by 40%", "", "Senior Engineer, Globex", "Led a team of five engineers", "Moved billing to Go"}
+		for i := range left {
+			y := 130 + float64(i)*14
+			for _, col := range []struct {
+				x    float64
+				text string
+			}{{54, left[i]}, {300, right[i]}} {
+				font := FontHelvetica
+				if strings.ToUpper(col.text) == col.text {
+					font = FontHelveticaBold
+				}
+				p.Text(col.x, y, font, 10, col.text)
+			}
+		}
+	},
+
+	// Three pages with a running header, a date at the top right and a page
+	// number footer
+	"header_footer.pdf": func(p *PDFWriter) {
+		bodies := [][]string{
+			{"EXPERIENCE", "Backend Engineer, Acme Corp", "Built the payment API in Go"},
+			{"EDUCATION", "MSc Computer Science, TU Berlin", "Thesis on stream processing"},
+			{"PROJECTS", "Open source contributor to Prometheus", "Maintainer of a Go rate limiter"},
+		}
+		for i, body := range bodies {
+			p.AddPage()
+			p.Text(54, 30, FontHelvetica, 9, "Jane Doe - Curriculum Vitae")
+			p.Text(480, 30, FontHelvetica, 9, "Updated 2024")
+			for j, line := range body {
+				font := FontHelvetica
+				if j == 0 {
+					font = FontHelveticaBold
+				}
+				p.Text(54, 100+float64(j)*14, font, 10, line)
+			}
+			p.Text(270, 820, FontHelvetica, 9, "Page "+string(rune('1'+i))+" of 3")
+		}
+	},
+
+	// Bullet items whose text wraps, once with a hyphenated word
+	"bullets.pdf": func(p *PDFWriter) {
+		p.AddPage()
+		p.Text(54, 90, FontHelveticaBold, 12, "EXPERIENCE")
+		p.Text(54, 110, FontHelvetica, 10, "Backend Engineer, Acme Corp")
+		y := 124.0
+		for _, item := range [][]string{
+			{"Built the payment API in Go, serving two million requests", "a day across three regions"},
+			{"Cut checkout latency by 40% with a read-through cache and con-", "nection pooling"},
+			{"Mentored four engineers"},
+		} {
+			p.Text(60, y, FontHelvetica, 10, "•")
+			for _, line := range item {
+				p.Text(72, y, FontHelvetica, 10, line)
+				y += 14
+			}
+		}
+		p.Text(54, y+14, FontHelveticaBold, 12, "EDUCATION")
+		p.Text(54, y+34, FontHelvetica, 10, "MSc Computer Science, TU Berlin")
+	},
+}
+
+// extractFixture extracts a testdata PDF, writing it first with -update.
+func extractFixture(t *testing.T, name string) string {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateFixtures {
+		p := NewPDFWriter()
+		pdfLayoutFixtures[name](p)
+		if err := os.WriteFile(path, p.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	doc, err := ExtractDocument(path)
+	if err != nil {
+		t.Fatalf("extracting %s: %v", name, err)
+	}
+	if doc.Format != FormatPDF {
+		t.Fatalf("%s was read as %q", name, doc.Format)
+	}
+	return doc.Text
+}
+
+// assertOrder checks that each of want appears in text after the one before.
+func assertOrder(t *testing.T, text string, want ...string) {
+	t.Helper()
+	at := 0
+	for _, w := range want {
+		i := strings.Index(text[at:], w)
+		if i < 0 {
+			t.Errorf("%q is missing or out of order in:\n%s", w, text)
+			return
+		}
+		at += i + len(w)
+	}
+}
+
+func TestPDFLayoutTwoColumns(t *testing.T) {
+	text := extractFixture(t, "two_column.pdf")
+
+	// The name, then the whole left column, then the whole right one
+	assertOrder(t, text,
+		"Jane Doe",
+		"SKILLS", "Go, PostgreSQL", "Kubernetes, Terraform", "gRPC, Kafka", "LANGUAGES", "English (native)", "German (B2)",
+		"EXPERIENCE", "Backend Engineer, Acme Corp", "Built the payment API in Go", "Cut latency by 40%",
+		"Senior Engineer, Globex", "Led a team of five engineers", "Moved billing to Go",
+	)
+
+	// No line mixes the columns
+	for _, line := range strings.Split(text, "\n") {
+		if strings.Contains(line, "\t") {
+			t.Errorf("line %q joins text of both columns", line)
+		}
+	}
+}
+
+func TestPDFLayoutHeadersAndFooters(t *testing.T) {
+	text := extractFixture(t, "header_footer.pdf")
+
+	for _, dropped := range []string{"Curriculum Vitae", "Updated 2024", "Page 1", "Page 2", "of 3"} {
+		if strings.Contains(text, dropped) {
+			t.Errorf("header or footer %q was kept:\n%s", dropped, text)
+		}
+	}
+	assertOrder(t, text,
+		"EXPERIENCE", "Backend Engineer, Acme Corp", "Built the payment API in Go",
+		"EDUCATION", "MSc Computer Science, TU Berlin", "Thesis on stream processing",
+		"PROJECTS", "Open source contributor to Prometheus", "Maintainer of a Go rate limiter",
+	)
+}
+
+func TestPDFLayoutPageNumberOnOnePage(t *testing.T) {
+	// A lone page keeps its header but not its page number
+	p := NewPDFWriter()
+	p.AddPage()
+	p.Text(54, 30, FontHelvetica, 9, "Jane Doe - Curriculum Vitae")
+	p.Text(54, 100, FontHelvetica, 10, "Backend Engineer, Acme Corp")
+	p.Text(270, 820, FontHelvetica, 9, "Page 1 of 1")
+
+	doc, err := ExtractPDF(writePDF(t, p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(doc.Text, "Curriculum Vitae") || strings.Contains(doc.Text, "Page 1") {
+		t.Errorf("want the header kept and the page number dropped:\n%s", doc.Text)
+	}
+}
+
+func TestPDFLayoutBullets(t *testing.T) {
+	text := extractFixture(t, "bullets.pdf")
+
+	want := "EXPERIENCE\n" +
+		"Backend Engineer, Acme Corp\n" +
+		"- Built the payment API in Go, serving two million requests a day across three regions\n" +
+		"- Cut checkout latency by 40% with a read-through cache and connection pooling\n" +
+		"- Mentored four engineers\n\n" +
+		"EDUCATION\n" +
+		"MSc Computer Science, TU Berlin"
+	if text != want {
+		t.Errorf("got:\n%s\n\nwant:\n%s", text, want)
+	}
+}
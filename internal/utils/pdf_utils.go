@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"strings"
 
@@ -8,30 +10,139 @@ import (
 )
 
 func ExtractTextFromPDF(filePath string) (string, error) {
-	f, r, err := pdf.Open(filePath)
+	doc, err := ExtractPDF(filePath)
 	if err != nil {
 		return "", err
 	}
-	defer f.Close()
+	return doc.Text, nil
+}
 
-	b, err := r.GetPlainText()
+// ExtractPDF reads a PDF's text page by page in reading order: columns one
+// after the other, without running headers, footers and page numbers, with
+// bullets as "- " and a blank line between sections. Pages the reader
+// cannot position the text of are read in content stream order instead.
+// Documents over Limits are refused.
+func ExtractPDF(filePath string) (*Document, error) {
+	f, r, err := pdf.Open(filePath)
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("%w: %v", ErrMalformedDocument, err)
+	}
+	defer f.Close()
+	if n := r.NumPage(); n > Limits.MaxPages {
+		return nil, fmt.Errorf("%w: %d pages, at most %d are read", ErrTooManyPages, n, Limits.MaxPages)
 	}
+	budget := newDecompressBudget(filePath)
 
-	content, err := io.ReadAll(b)
-	if err != nil {
-		return "", err
+	var pages []*layoutPage
+	plain := map[int]string{} // text of the pages without positions
+	fonts := make(map[string]*pdf.Font)
+	for i := 1; i <= r.NumPage(); i++ {
+		p := r.Page(i)
+		if err := checkPageContent(p, budget); err != nil {
+			return nil, err
+		}
+		for _, name := range p.Fonts() { // cache fonts so charmaps are parsed once
+			if _, ok := fonts[name]; !ok {
+				font := p.Font(name)
+				fonts[name] = &font
+			}
+		}
+		page := &layoutPage{glyphs: pdfGlyphs(p)}
+		page.bottom, page.top = pageBounds(p)
+		if page.positioned() {
+			page.groupLines()
+		} else {
+			text, err := p.GetPlainText(fonts)
+			if err != nil {
+				return nil, err
+			}
+			plain[len(pages)] = text
+		}
+		pages = append(pages, page)
 	}
+	dropHeadersAndFooters(pages)
 
-	return string(content), nil
+	doc := &Document{Format: FormatPDF}
+	var out layoutText
+	bodySize := bodyTextSize(pages)
+	for i, page := range pages {
+		out.blankLine()
+		doc.Pages = append(doc.Pages, DocumentPage{Start: len(out.b), Glyphs: page.glyphs})
+		if text, ok := plain[i]; ok {
+			out.sep(text)
+			continue
+		}
+		page.write(&out, page.readingOrder(page.lines, 0), bodySize)
+	}
+	out.trim(" \n")
+	doc.Text, doc.textGlyphs = string(out.b), out.glyphs
+	return doc, nil
 }
 
+// checkPageContent decompresses a page's content streams once, failing if
+// they use up the document's budget. The reader itself would read them
+// into memory whatever their size.
+func checkPageContent(p pdf.Page, budget *decompressBudget) error {
+	contents := p.V.Key("Contents")
+	streams := []pdf.Value{contents}
+	if contents.Kind() == pdf.Array {
+		streams = streams[:0]
+		for i := 0; i < contents.Len(); i++ {
+			streams = append(streams, contents.Index(i))
+		}
+	}
+	for _, s := range streams {
+		if s.Kind() != pdf.Stream {
+			continue
+		}
+		rc := s.Reader()
+		_, err := io.Copy(io.Discard, budget.reader(rc))
+		rc.Close()
+		// Other read errors are the reader's to handle
+		if errors.Is(err, ErrDecompressionBomb) {
+			return err
+		}
+	}
+	return nil
+}
+
+// pageBounds returns the bottom and top of a page's media box, which the
+// page may inherit from the page tree.
+func pageBounds(p pdf.Page) (bottom, top float64) {
+	for v := p.V; !v.IsNull(); v = v.Key("Parent") {
+		if box := v.Key("MediaBox"); box.Len() == 4 {
+			return box.Index(1).Float64(), box.Index(3).Float64()
+		}
+	}
+	return 0, 0
+}
+
+// pdfGlyphs returns the positioned text of a page, or nothing if the reader
+// cannot lay the page out.
+func pdfGlyphs(p pdf.Page) (glyphs []Glyph) {
+	defer func() {
+		if r := recover(); r != nil {
+			glyphs = nil
+		}
+	}()
+	if p.V.IsNull() || p.V.Key("Contents").Kind() == pdf.Null {
+		return nil
+	}
+	for _, t := range p.Content().Text {
+		glyphs = append(glyphs, Glyph{S: t.S, X: t.X, Y: t.Y, W: t.W, Size: t.FontSize, Font: t.Font})
+	}
+	return glyphs
+}
+
+// SanitizeText makes extracted text safe to quote in a prompt. Line breaks
+// are kept, so the model still sees the document's sections.
 func SanitizeText(input string) string {
 	s := strings.ReplaceAll(input, `"`, `'`)
-	s = strings.ReplaceAll(s, "\n", " ")
-	s = strings.ReplaceAll(s, "\r", " ")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
 	s = strings.Map(func(r rune) rune {
+		if r == '\n' {
+			return r
+		}
 		if r < 32 || r == 127 {
 			return ' '
 		}
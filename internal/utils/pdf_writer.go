@@ -0,0 +1,267 @@
+package utils
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// PDF fonts. These are standard fonts every PDF viewer has, so text is
+// written as real text (selectable and searchable) without embedding a font.
+const (
+	FontHelvetica     = "Helvetica"
+	FontHelveticaBold = "Helvetica-Bold"
+	FontTimes         = "Times-Roman"
+	FontTimesBold     = "Times-Bold"
+)
+
+// A4 page size in points
+const (
+	PageWidthA4  = 595.28
+	PageHeightA4 = 841.89
+)
+
+// Glyph widths of ' ' to '~' in thousandths of the font size, from the fonts' AFM files
+var fontWidths = map[string]*[95]int{
+	FontHelvetica: {
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	},
+	FontHelveticaBold: {
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+	FontTimes: {
+		250, 333, 408, 500, 500, 833, 778, 180, 333, 333, 500, 564, 250, 333, 250, 278,
+		500, 500, 500, 500, 500, 500, 500, 500, 500, 500, 278, 278, 564, 564, 564, 444,
+		921, 722, 667, 667, 722, 611, 556, 722, 722, 333, 389, 722, 611, 889, 722, 722,
+		556, 722, 667, 556, 611, 722, 722, 944, 722, 722, 611, 333, 278, 333, 469, 500,
+		333, 444, 500, 444, 500, 444, 333, 500, 500, 278, 278, 500, 278, 778, 500, 500,
+		500, 500, 333, 389, 278, 500, 500, 722, 500, 500, 444, 480, 200, 480, 541,
+	},
+	FontTimesBold: {
+		250, 333, 555, 500, 500, 1000, 833, 278, 333, 333, 500, 570, 250, 333, 250, 278,
+		500, 500, 500, 500, 500, 500, 500, 500, 500, 500, 333, 333, 570, 570, 570, 500,
+		930, 722, 667, 722, 722, 667, 611, 778, 778, 389, 500, 778, 667, 944, 722, 778,
+		611, 778, 722, 556, 667, 722, 722, 1000, 722, 722, 667, 333, 278, 333, 581, 500,
+		333, 500, 556, 444, 556, 444, 333, 500, 556, 278, 333, 556, 278, 833, 556, 500,
+		556, 556, 444, 389, 333, 556, 500, 722, 500, 500, 444, 394, 220, 394, 520,
+	},
+}
+
+// winAnsiCodes maps the characters of Windows-1252 above Latin-1's C1 range
+// to their codes; Latin-1 letters keep their own.
+var winAnsiCodes = sync.OnceValue(func() map[rune]byte {
+	codes := make(map[rune]byte, len(cp1252High))
+	for b, r := range cp1252High {
+		codes[r] = b
+	}
+	return codes
+})
+
+// winAnsi encodes s for a standard font; characters it cannot show become '?'.
+func winAnsi(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		b, ok := winAnsiByte(r)
+		if !ok {
+			b = '?'
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+func winAnsiByte(r rune) (byte, bool) {
+	switch {
+	case r == '\t':
+		return ' ', true
+	case r >= 0x20 && r < 0x7F, r >= 0xA0 && r <= 0xFF:
+		return byte(r), true
+	}
+	b, ok := winAnsiCodes()[r]
+	return b, ok
+}
+
+// UnsupportedPDFChars returns the characters of s that the standard fonts
+// cannot show (Cyrillic, CJK, emoji...), once each in order of appearance.
+func UnsupportedPDFChars(s string) []rune {
+	var chars []rune
+	for _, r := range s {
+		if _, ok := winAnsiByte(r); !ok && r != '\n' && !slices.Contains(chars, r) {
+			chars = append(chars, r)
+		}
+	}
+	return chars
+}
+
+// TextWidth is the width of s in points when set in font at size.
+func TextWidth(font string, size float64, s string) float64 {
+	widths := fontWidths[font]
+	if widths == nil {
+		widths = fontWidths[FontHelvetica]
+	}
+	total := 0
+	for _, b := range winAnsi(s) {
+		total += glyphWidth(widths, b)
+	}
+	return float64(total) * size / 1000
+}
+
+func glyphWidth(widths *[95]int, b byte) int {
+	switch {
+	case b >= 0x20 && b < 0x7F:
+		return widths[b-0x20]
+	case b == 0x95: // bullet
+		return 350
+	default: // accented letters and dashes are about as wide as an "o"
+		return widths['o'-0x20]
+	}
+}
+
+// fontWidthsArray lists a font's widths of codes 32 to 255. Viewers know
+// the standard fonts' widths, but text extractors position glyphs with it.
+func fontWidthsArray(font string) string {
+	widths := fontWidths[font]
+	if widths == nil {
+		widths = fontWidths[FontHelvetica]
+	}
+	var b strings.Builder
+	for c := 32; c <= 255; c++ {
+		fmt.Fprintf(&b, "%d ", glyphWidth(widths, byte(c)))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// PDFWriter draws text and lines on pages and writes them out as a PDF.
+// Positions are in points from the top left corner of the page.
+type PDFWriter struct {
+	Width, Height float64
+	Title         string
+
+	pages []*bytes.Buffer
+	fonts []string // in resource order: F1, F2...
+	color string   // current fill color operator
+}
+
+func NewPDFWriter() *PDFWriter {
+	return &PDFWriter{Width: PageWidthA4, Height: PageHeightA4, color: "0 0 0 rg"}
+}
+
+// AddPage starts a new page; drawing goes to the last page.
+func (p *PDFWriter) AddPage() {
+	p.pages = append(p.pages, &bytes.Buffer{})
+}
+
+// SetColor sets the RGB color (0-1) of the text and lines drawn next.
+func (p *PDFWriter) SetColor(r, g, b float64) {
+	p.color = fmt.Sprintf("%.3f %.3f %.3f rg", r, g, b)
+}
+
+func (p *PDFWriter) page() *bytes.Buffer {
+	if len(p.pages) == 0 {
+		p.AddPage()
+	}
+	return p.pages[len(p.pages)-1]
+}
+
+func (p *PDFWriter) fontRef(font string) string {
+	for i, f := range p.fonts {
+		if f == font {
+			return fmt.Sprintf("F%d", i+1)
+		}
+	}
+	p.fonts = append(p.fonts, font)
+	return fmt.Sprintf("F%d", len(p.fonts))
+}
+
+// Text draws s with its baseline at y.
+func (p *PDFWriter) Text(x, y float64, font string, size float64, s string) {
+	fmt.Fprintf(p.page(), "%s BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n",
+		p.color, p.fontRef(font), size, x, p.Height-y, escapePDFString(winAnsi(s)))
+}
+
+// Line draws a straight line width points thick.
+func (p *PDFWriter) Line(x1, y1, x2, y2, width float64) {
+	stroke := strings.TrimSuffix(p.color, "rg") + "RG"
+	fmt.Fprintf(p.page(), "%s %.2f w %.2f %.2f m %.2f %.2f l S\n",
+		stroke, width, x1, p.Height-y1, x2, p.Height-y2)
+}
+
+func escapePDFString(b []byte) string {
+	var out strings.Builder
+	for _, c := range b {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			out.WriteByte('\\')
+			out.WriteByte(c)
+		case c >= 0x80:
+			fmt.Fprintf(&out, "\\%03o", c)
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String()
+}
+
+// Bytes writes out the document.
+func (p *PDFWriter) Bytes() []byte {
+	p.page() // a document has at least one page
+
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1: catalog, 2: page tree, 3: info, then fonts, then a page and its contents per page
+	fontsStart := 4
+	pagesStart := fontsStart + len(p.fonts)
+	var kids, fonts strings.Builder
+	for i := range p.pages {
+		fmt.Fprintf(&kids, "%d 0 R ", pagesStart+2*i)
+	}
+	for i := range p.fonts {
+		fmt.Fprintf(&fonts, "/F%d %d 0 R ", i+1, fontsStart+i)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.TrimSpace(kids.String()), len(p.pages)))
+	object(fmt.Sprintf("<< /Title (%s) /Producer (resume-parser) >>", escapePDFString(winAnsi(p.Title))))
+	for _, font := range p.fonts {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding /FirstChar 32 /LastChar 255 /Widths [%s] >>",
+			font, fontWidthsArray(font)))
+	}
+	for i, content := range p.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << %s>> >> /Contents %d 0 R >>",
+			p.Width, p.Height, fonts.String(), pagesStart+2*i+1))
+
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		zw.Write(content.Bytes())
+		zw.Close()
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", z.Len(), z.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writePDF writes a generated PDF into the test's temporary directory.
+func writePDF(t *testing.T, p *PDFWriter) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "out.pdf")
+	if err := os.WriteFile(path, p.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPDFWriterRoundTrip(t *testing.T) {
+	p := NewPDFWriter()
+	p.Title = "José Müller - Resume"
+	p.AddPage()
+	lines := []struct {
+		font string
+		size float64
+		text string
+	}{
+		{FontHelveticaBold, 20, "José Müller"},
+		{FontHelvetica, 11, "Développeur – Zürich, Straße 5 (Büro)"},
+		{FontTimes, 11, "Café “Crème” façade € 1.200 naïve"},
+		{FontTimesBold, 11, "Experience"},
+		{FontTimes, 11, "Backend Engineer at Ærø Systems, 2019 - 2023"},
+	}
+	y := 60.0
+	for _, l := range lines {
+		p.Text(54, y, l.font, l.size, l.text)
+		y += l.size * 2
+	}
+	p.AddPage()
+	p.Text(54, 60, FontHelvetica, 11, `Second page with \ and (parentheses)`)
+
+	doc, err := ExtractPDF(writePDF(t, p))
+	if err != nil {
+		t.Fatalf("the generated PDF does not parse: %v", err)
+	}
+	if len(doc.Pages) != 2 {
+		t.Errorf("got %d pages, want 2", len(doc.Pages))
+	}
+	for _, l := range lines {
+		if !strings.Contains(doc.Text, l.text) {
+			t.Errorf("text %q did not round-trip; extracted:\n%s", l.text, doc.Text)
+		}
+	}
+	if !strings.Contains(doc.Text, `Second page with \ and (parentheses)`) {
+		t.Errorf("escaped text did not round-trip; extracted:\n%s", doc.Text)
+	}
+	if i, j := strings.Index(doc.Text, "José Müller"), strings.Index(doc.Text, "Experience"); i < 0 || j < i {
+		t.Errorf("lines are out of order:\n%s", doc.Text)
+	}
+}
+
+func TestUnsupportedPDFChars(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"José Müller – Café “Crème” € •", ""},
+		{"Line one\nLine\ttwo", ""},
+		{"Анна Петрова", "АнаПетров"},
+		{"Zoë Chen 陈 🚀 陈", "陈🚀"},
+		{"Łukasz Kowalski", "Ł"},
+	}
+	for _, tt := range tests {
+		if got := string(UnsupportedPDFChars(tt.text)); got != tt.want {
+			t.Errorf("UnsupportedPDFChars(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+)
+
+var (
+	// One date of a range, tried in order: "Jan 2021", "01/2021", "2021-01", "2021"
+	periodDatePattern = regexp.MustCompile(`(?i)\b(jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec|janv|févr|mär|mai|juin|juil|okt|dez|ene|abr|ago|dic|fev|set|out)[a-zéûçä]*\.?,?\s*(?:de\s+)?((?:19|20)\d{2})\b` +
+		`|\b(\d{1,2})[/.](\d{1,2}[/.])?((?:19|20)\d{2})\b` +
+		`|\b((?:19|20)\d{2})[-/.](\d{1,2})\b` +
+		`|\b((?:19|20)\d{2})\b`)
+	ongoingPattern  = regexp.MustCompile(`(?i)\b(present|current|currently|now|today|date|ongoing|heute|aktuell|jetzt|actuel|presente|actualidad|actualmente|actual|hoy|atual|atualmente|momento)\b|वर्तमान|[-–—]\s*$|^\s*(since|from|seit|depuis|desde)\b`)
+	durationPattern = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)?)\s*(years?|yrs?|y|months?|mos?|mths?|m)\b`)
+
+	monthPrefixes = map[string]time.Month{
+		"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+		"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+		"sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+		"janv": time.January, "févr": time.February, "mär": time.March, "mai": time.May,
+		"juin": time.June, "juil": time.July, "okt": time.October, "dez": time.December,
+		"ene": time.January, "abr": time.April, "ago": time.August, "dic": time.December,
+		"fev": time.February, "set": time.September, "out": time.October,
+	}
+)
+
+type periodDate struct {
+	year  int
+	month time.Month // 0 when only the year is known
+}
+
+func (d periodDate) time() *time.Time {
+	t := time.Date(d.year, max(d.month, time.January), 1, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+// ParseDateRange normalizes a free-text period such as "2020-2024",
+// "Jan 2021 – Present", "03/2019 - 05/2020" or "3 yrs". Text it cannot read
+// gives an empty range.
+func ParseDateRange(raw string) model.DateRange {
+	var dates []periodDate
+	for _, m := range periodDatePattern.FindAllStringSubmatch(raw, 2) {
+		var d periodDate
+		switch {
+		case m[1] != "": // Jan 2021
+			d.year, _ = strconv.Atoi(m[2])
+			d.month = monthPrefixes[strings.ToLower(m[1])]
+		case m[5] != "": // 01/2021 or 15/01/2021
+			d.year, _ = strconv.Atoi(m[5])
+			n, _ := strconv.Atoi(m[3])
+			if m[4] != "" {
+				n, _ = strconv.Atoi(strings.TrimRight(m[4], "/."))
+			}
+			if n >= 1 && n <= 12 {
+				d.month = time.Month(n)
+			}
+		case m[6] != "": // 2021-01
+			d.year, _ = strconv.Atoi(m[6])
+			if n, _ := strconv.Atoi(m[7]); n >= 1 && n <= 12 {
+				d.month = time.Month(n)
+			}
+		default:
+			d.year, _ = strconv.Atoi(m[8])
+		}
+		dates = append(dates, d)
+	}
+
+	if len(dates) == 0 {
+		return parseDuration(raw)
+	}
+
+	r := model.DateRange{Start: dates[0].time(), Precision: model.PeriodPrecisionMonth}
+	switch {
+	case len(dates) == 2:
+		r.End = dates[1].time()
+		if dates[1].month == 0 {
+			r.Precision = model.PeriodPrecisionYear
+		}
+	case ongoingPattern.MatchString(raw):
+		r.Ongoing = true
+	default:
+		r.End = dates[0].time() // a single date, e.g. a graduation year
+	}
+	if dates[0].month == 0 {
+		r.Precision = model.PeriodPrecisionYear
+	}
+	if r.Precision == model.PeriodPrecisionYear {
+		// Mixed precision is read at the coarser one
+		r.Start = periodDate{year: dates[0].year}.time()
+		if r.End != nil {
+			r.End = periodDate{year: r.End.Year()}.time()
+		}
+	}
+	if r.End != nil && r.End.Before(*r.Start) {
+		r.Start, r.End = r.End, r.Start
+	}
+	return r
+}
+
+// parseDuration reads a length such as "3 yrs" or "2 years 6 months".
+func parseDuration(raw string) model.DateRange {
+	months := 0.0
+	for _, m := range durationPattern.FindAllStringSubmatch(raw, -1) {
+		n, err := strconv.ParseFloat(strings.Replace(m[1], ",", ".", 1), 64)
+		if err != nil {
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(m[2]), "y") {
+			n *= 12
+		}
+		months += n
+	}
+	if months == 0 {
+		return model.DateRange{}
+	}
+	return model.DateRange{Precision: model.PeriodPrecisionDuration, DurationMonths: int(math.Round(months))}
+}
+
+// SummarizeExperience adds up a user's positions. Overlapping years are the
+// time spent in two or more positions at once; positions known only by their
+// length are counted in the totals but cannot overlap.
+func SummarizeExperience(experience []model.Experience, now time.Time) model.ExperienceSummary {
+	type span struct{ from, to time.Time }
+	var spans []span
+	total, lengthOnly := 0, 0
+	for _, e := range experience {
+		if from, to, ok := e.Period.Span(now); ok {
+			spans = append(spans, span{from, to})
+			total += model.MonthsBetween(from, to)
+		} else if e.Period.Precision == model.PeriodPrecisionDuration {
+			lengthOnly += e.Period.DurationMonths
+		}
+	}
+
+	// Merge the dated spans to find how much of the time they cover
+	sort.Slice(spans, func(i, j int) bool { return spans[i].from.Before(spans[j].from) })
+	covered := 0
+	for i := 0; i < len(spans); {
+		from, to := spans[i].from, spans[i].to
+		for i++; i < len(spans) && !spans[i].from.After(to); i++ {
+			if spans[i].to.After(to) {
+				to = spans[i].to
+			}
+		}
+		covered += model.MonthsBetween(from, to)
+	}
+
+	return model.ExperienceSummary{
+		TotalYears:       monthsToYears(total + lengthOnly),
+		OverlappingYears: monthsToYears(total - covered),
+		NetYears:         monthsToYears(covered + lengthOnly),
+	}
+}
+
+func monthsToYears(months int) float64 {
+	return math.Round(float64(months)/12*10) / 10
+}
@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// RTF destinations that hold metadata rather than document text
+var rtfSkipDestinations = map[string]bool{
+	"fonttbl": true, "colortbl": true, "stylesheet": true, "info": true, "pict": true,
+	"object": true, "themedata": true, "colorschememapping": true, "latentstyles": true,
+	"datastore": true, "listtable": true, "listoverridetable": true, "rsidtbl": true,
+	"generator": true, "xmlnstbl": true, "mmathPr": true, "filetbl": true, "revtbl": true,
+}
+
+// Control words that stand for a character
+var rtfSymbols = map[string]string{
+	"par": "\n", "line": "\n", "sect": "\n", "page": "\n", "row": "\n",
+	"tab": "\t", "cell": " | ", "bullet": "•", "emdash": "—", "endash": "–",
+	"lquote": "‘", "rquote": "’", "ldblquote": "“", "rdblquote": "”",
+	"emspace": " ", "enspace": " ", "qmspace": " ",
+}
+
+// ExtractTextFromRTF reads the text of a basic RTF document: paragraphs,
+// tabs, table cells, hex escapes and Unicode characters. Fonts, styles,
+// pictures and other metadata are skipped.
+func ExtractTextFromRTF(filePath string) (string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	return tidyLines(rtfText(string(data))), nil
+}
+
+func rtfText(src string) string {
+	type group struct {
+		skip   bool
+		ucSkip int // fallback characters that follow a \u escape
+	}
+
+	var out strings.Builder
+	stack := []group{{ucSkip: 1}}
+	pendingSkip := 0 // fallback characters still to drop after \u
+	rowStart := true
+	pendingCell := false // a \cell separator, dropped if the row ends first
+
+	emit := func(s string) {
+		if stack[len(stack)-1].skip {
+			return
+		}
+		if pendingSkip > 0 {
+			pendingSkip--
+			return
+		}
+		switch {
+		case s == " | ":
+			pendingCell = true
+			return
+		case s == "\n":
+			pendingCell = false
+		case pendingCell:
+			out.WriteString(" | ")
+			pendingCell = false
+		}
+		out.WriteString(s)
+		rowStart = s == "\n"
+	}
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch c {
+		case '{':
+			top := stack[len(stack)-1]
+			stack = append(stack, group{skip: top.skip, ucSkip: top.ucSkip})
+			// "{\*\dest ...}" marks a destination readers may ignore
+			if strings.HasPrefix(src[i+1:], `\*`) {
+				stack[len(stack)-1].skip = true
+			}
+		case '}':
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+			pendingSkip = 0
+		case '\\':
+			if i+1 >= len(src) {
+				break
+			}
+			next := src[i+1]
+			switch {
+			case next == '\'' && i+3 < len(src):
+				// \'hh: a character in the document's code page, read as Windows-1252
+				if n, err := strconv.ParseUint(src[i+2:i+4], 16, 8); err == nil {
+					emit(string(cp1252Rune(byte(n))))
+				}
+				i += 3
+			case next == '\\' || next == '{' || next == '}':
+				emit(string(next))
+				i++
+			case next == '~':
+				emit(" ")
+				i++
+			case next == '-' || next == '_':
+				if next == '_' {
+					emit("-")
+				}
+				i++
+			case next == '\n' || next == '\r':
+				emit("\n")
+				i++
+			case isASCIILetter(next):
+				j := i + 1
+				for j < len(src) && isASCIILetter(src[j]) {
+					j++
+				}
+				word := src[i+1 : j]
+				k := j
+				if k < len(src) && (src[k] == '-' || src[k] >= '0' && src[k] <= '9') {
+					k++
+					for k < len(src) && src[k] >= '0' && src[k] <= '9' {
+						k++
+					}
+				}
+				param, hasParam := 0, k > j
+				if hasParam {
+					param, _ = strconv.Atoi(src[j:k])
+				}
+				// A single space delimits the control word and is not text
+				if k < len(src) && src[k] == ' ' {
+					k++
+				}
+				i = k - 1
+
+				top := &stack[len(stack)-1]
+				switch {
+				case rtfSkipDestinations[word]:
+					top.skip = true
+				case word == "uc" && hasParam:
+					top.ucSkip = param
+				case word == "u" && hasParam:
+					if param < 0 {
+						param += 65536
+					}
+					emit(string(rune(param)))
+					pendingSkip = top.ucSkip
+				case word == "trowd" && !rowStart:
+					emit("\n")
+				default:
+					if s, ok := rtfSymbols[word]; ok {
+						emit(s)
+					}
+				}
+			default:
+				i++
+			}
+		case '\r', '\n':
+			// Raw line breaks in RTF source are not part of the text
+		default:
+			emit(src[i : i+1]) // bytes of UTF-8 text pass through unchanged
+		}
+	}
+	return out.String()
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// Windows-1252 characters in 0x80-0x9F that differ from Latin-1
+var cp1252High = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡',
+	0x88: 'ˆ', 0x89: '‰', 0x8A: 'Š', 0x8B: '‹', 0x8C: 'Œ', 0x8E: 'Ž',
+	0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—',
+	0x98: '˜', 0x99: '™', 0x9A: 'š', 0x9B: '›', 0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+}
+
+func cp1252Rune(b byte) rune {
+	if r, ok := cp1252High[b]; ok {
+		return r
+	}
+	return rune(b)
+}
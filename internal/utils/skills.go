@@ -0,0 +1,37 @@
+package utils
+
+import (
+	_ "embed"
+	"strings"
+
+	"github.com/satyam-svg/resume-parser/internal/model"
+)
+
+//go:embed data/skills.csv
+var skillsCSV []byte
+
+// DefaultSkills returns the built-in skill taxonomy.
+func DefaultSkills() []model.Skill {
+	var skills []model.Skill
+	for _, rec := range readDataCSV(skillsCSV) {
+		if len(rec) < 3 {
+			continue
+		}
+		skill := model.Skill{Name: rec[0], Category: rec[1], Parent: rec[2], Aliases: model.StringList{}}
+		if len(rec) > 3 && rec[3] != "" {
+			for _, a := range strings.Split(rec[3], "|") {
+				if a = strings.TrimSpace(a); a != "" {
+					skill.Aliases = append(skill.Aliases, a)
+				}
+			}
+		}
+		skills = append(skills, skill)
+	}
+	return skills
+}
+
+// SkillKey folds a skill name for matching: "  Node.JS " and "node.js" are
+// the same skill.
+func SkillKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"bytes"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ExtractTextFromPlain reads a plain text resume, dropping a UTF-8 byte order
+// mark and normalizing line endings. Bytes that are not valid UTF-8 are read
+// as Latin-1, which is what most legacy exports turn out to be.
+func ExtractTextFromPlain(filePath string) (string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	return tidyLines(decodeText(data)), nil
+}
+
+// ExtractTextFromMarkdown reads a Markdown resume and strips its syntax while
+// keeping headings, list items and table rows on their own lines.
+func ExtractTextFromMarkdown(filePath string) (string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	return tidyLines(markdownText(decodeText(data))), nil
+}
+
+func decodeText(data []byte) string {
+	data = bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))
+	s := string(data)
+	if !utf8.ValidString(s) {
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		s = string(runes)
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\r", "\n")
+}
+
+// tidyLines trims trailing spaces and collapses runs of blank lines.
+func tidyLines(s string) string {
+	var out []string
+	blank := false
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimRight(line, " \t\u00a0")
+		if strings.TrimSpace(line) == "" {
+			if !blank && len(out) > 0 {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+var (
+	mdHeading    = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdRule       = regexp.MustCompile(`^\s{0,3}([-*_=])(\s*([-*_=]))*\s*$`)
+	mdBullet     = regexp.MustCompile(`^(\s*)[-*+]\s+(\[[ xX]\]\s+)?`)
+	mdQuote      = regexp.MustCompile(`^\s{0,3}>\s?`)
+	mdTableSep   = regexp.MustCompile(`^\s*\|?\s*:?-{2,}:?\s*(\|\s*:?-{2,}:?\s*)*\|?\s*$`)
+	mdImage      = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)(\s+"[^"]*")?\)`)
+	mdAutolink   = regexp.MustCompile(`<((?:https?://|mailto:)[^>\s]+)>`)
+	mdStrong     = regexp.MustCompile(`(\*\*|__)(\S(?:.*?\S)?)(\*\*|__)`)
+	mdEmphasis   = regexp.MustCompile(`(^|[^\w*])[*_](\S(?:[^*_]*?\S)?)[*_]($|[^\w*])`)
+	mdStrike     = regexp.MustCompile(`~~(.+?)~~`)
+	mdInlineCode = regexp.MustCompile("`+([^`]+)`+")
+	mdEscape     = regexp.MustCompile("\\\\([\\\\`*_{}\\[\\]()#+\\-.!|~>])")
+)
+
+// markdownText renders Markdown as plain text.
+func markdownText(s string) string {
+	var out strings.Builder
+	inFence := false
+
+	for _, line := range strings.Split(s, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			out.WriteString(line)
+			out.WriteString("\n")
+			continue
+		}
+
+		if m := mdHeading.FindStringSubmatch(line); m != nil {
+			out.WriteString("\n")
+			out.WriteString(markdownInline(m[2]))
+			out.WriteString("\n")
+			continue
+		}
+		// Horizontal rules and setext heading underlines
+		if mdRule.MatchString(line) && len(trimmed) >= 3 {
+			out.WriteString("\n")
+			continue
+		}
+		if mdTableSep.MatchString(line) && strings.Contains(line, "|") {
+			continue
+		}
+
+		for mdQuote.MatchString(line) {
+			line = mdQuote.ReplaceAllString(line, "")
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "|") {
+			cells := strings.Split(strings.Trim(strings.TrimSpace(line), "|"), "|")
+			for i := range cells {
+				cells[i] = strings.TrimSpace(cells[i])
+			}
+			line = strings.Join(cells, " | ")
+		}
+		line = mdBullet.ReplaceAllString(line, "$1- ")
+
+		out.WriteString(markdownInline(line))
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+func markdownInline(s string) string {
+	s = mdInlineCode.ReplaceAllString(s, "$1")
+	s = mdImage.ReplaceAllString(s, "$1")
+	s = mdLink.ReplaceAllStringFunc(s, func(m string) string {
+		parts := mdLink.FindStringSubmatch(m)
+		text, url := parts[1], parts[2]
+		if text == url || strings.TrimPrefix(url, "mailto:") == text {
+			return text
+		}
+		return text + " (" + url + ")"
+	})
+	s = mdAutolink.ReplaceAllStringFunc(s, func(m string) string {
+		return strings.TrimPrefix(mdAutolink.FindStringSubmatch(m)[1], "mailto:")
+	})
+	s = mdStrong.ReplaceAllString(s, "$2")
+	s = mdEmphasis.ReplaceAllString(s, "$1$2$3")
+	s = mdStrike.ReplaceAllString(s, "$1")
+	return mdEscape.ReplaceAllString(s, "$1")
+}
+
+// looksLikeMarkdown guesses whether plain text is written in Markdown.
+func looksLikeMarkdown(s string) bool {
+	score := 0
+	for _, line := range strings.Split(s, "\n") {
+		switch {
+		case mdHeading.MatchString(line):
+			score += 2
+		case strings.HasPrefix(strings.TrimSpace(line), "```"):
+			score += 2
+		case mdTableSep.MatchString(line) && strings.Contains(line, "|"):
+			score += 2
+		case mdBullet.MatchString(line):
+			score++
+		}
+		if mdLink.MatchString(line) || mdStrong.MatchString(line) {
+			score++
+		}
+	}
+	return score >= 3
+}